@@ -1,21 +1,411 @@
 package aws
 
+import (
+	"context"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	appsync_sdk "github.com/aws/aws-sdk-go-v2/service/appsync"
+	"github.com/aws/aws-sdk-go-v2/service/appsync/types"
+)
+
 type appsync struct {
-	aws AWS
+	aws    AWS
+	client *appsync_sdk.Client
 }
 
 type AppSync interface {
-	Regions() ([]string, error)
+	Regions(ctx context.Context) ([]string, error)
+	ListAPIs(ctx context.Context, region string) ([]GraphqlAPI, error)
+	ExportAPI(ctx context.Context, region string, apiID string) (*APIExport, error)
+}
+
+// GraphqlAPI is an AppSync GraphQL API
+type GraphqlAPI struct {
+	ID                 string
+	Name               string
+	ARN                string
+	AuthenticationType string
+	XRayEnabled        bool
+	Tags               map[string]string
+
+	// set for AWS_LAMBDA authentication
+	AuthorizerURI                string
+	AuthorizerResultTTLInSeconds int64
+	IdentityValidationExpression string
+
+	// set for AMAZON_COGNITO_USER_POOLS authentication
+	UserPoolID            string
+	UserPoolRegion        string
+	UserPoolDefaultAction string
+	AppIDClientRegex      string
+
+	// set for OPENID_CONNECT authentication
+	OIDCIssuer   string
+	OIDCClientID string
+
+	// set when logging is enabled
+	LogRoleARN            string
+	FieldLogLevel         string
+	ExcludeVerboseContent bool
+
+	// other authentication types accepted by the API
+	AdditionalAuthenticationProviders []AuthenticationProvider
+}
+
+// AuthenticationProvider is an additional authentication type of an AppSync API
+type AuthenticationProvider struct {
+	AuthenticationType string
+
+	// set for AWS_LAMBDA authentication
+	AuthorizerURI                string
+	AuthorizerResultTTLInSeconds int64
+	IdentityValidationExpression string
+
+	// set for AMAZON_COGNITO_USER_POOLS authentication
+	UserPoolID       string
+	UserPoolRegion   string
+	AppIDClientRegex string
+
+	// set for OPENID_CONNECT authentication
+	OIDCIssuer   string
+	OIDCClientID string
+}
+
+// DataSource is a data source attached to an AppSync API
+type DataSource struct {
+	Name           string
+	Type           string
+	Description    string
+	ServiceRoleARN string
+
+	LambdaFunctionARN string
+	TableName         string
+	TableRegion       string
+	HTTPEndpoint      string
+	EventBusARN       string
+
+	// set for RELATIONAL_DATABASE data sources
+	DBClusterIdentifier string
+	DatabaseName        string
+	DatabaseSchema      string
+	DatabaseRegion      string
+	SecretStoreARN      string
+
+	// set for AMAZON_OPENSEARCH_SERVICE and AMAZON_ELASTICSEARCH data sources
+	SearchEndpoint string
+	SearchRegion   string
+}
+
+// Resolver resolves a field of a GraphQL type
+type Resolver struct {
+	TypeName       string
+	FieldName      string
+	Kind           string
+	DataSourceName string
+	Code           string
+	RuntimeName    string
+	RuntimeVersion string
+	// request and response mapping templates of VTL resolvers
+	RequestTemplate  string
+	ResponseTemplate string
+	// IDs of the functions run by PIPELINE resolvers
+	Functions []string
+}
+
+// Function is a pipeline resolver function
+type Function struct {
+	ID               string
+	Name             string
+	Description      string
+	DataSourceName   string
+	Code             string
+	RuntimeName      string
+	RuntimeVersion   string
+	FunctionVersion  string
+	RequestTemplate  string
+	ResponseTemplate string
+}
+
+// APIExport is everything needed to recreate an AppSync API
+type APIExport struct {
+	API         GraphqlAPI
+	Schema      string
+	DataSources []DataSource
+	Resolvers   []Resolver
+	Functions   []Function
 }
 
 // NewAppSync creates a new AppSync client
 func NewAppSync(aws AWS) AppSync {
 	return &appsync{
-		aws: aws,
+		aws:    aws,
+		client: appsync_sdk.NewFromConfig(aws.Config()),
 	}
 }
 
 // Regions returns a list of regions that the AppSync service is
-func (a appsync) Regions() ([]string, error) {
-	return a.aws.Regions("appsync")
+func (a appsync) Regions(ctx context.Context) ([]string, error) {
+	return a.aws.Regions(ctx, "appsync")
+}
+
+// inRegion sends a request to the region instead of the region of the configuration
+func inRegion(region string) func(*appsync_sdk.Options) {
+	return func(o *appsync_sdk.Options) {
+		o.Region = region
+	}
+}
+
+// ListAPIs lists the GraphQL APIs in the region
+func (a appsync) ListAPIs(ctx context.Context, region string) ([]GraphqlAPI, error) {
+	var apis []GraphqlAPI
+
+	input := &appsync_sdk.ListGraphqlApisInput{}
+	for {
+		result, err := a.client.ListGraphqlApis(ctx, input, inRegion(region))
+		if err != nil {
+			return apis, err
+		}
+
+		for _, api := range result.GraphqlApis {
+			apis = append(apis, newGraphqlAPI(api))
+		}
+
+		if result.NextToken == nil {
+			return apis, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// ExportAPI downloads the schema, data sources, resolvers and functions of the API
+func (a appsync) ExportAPI(ctx context.Context, region string, apiID string) (*APIExport, error) {
+	id := aws_sdk.String(apiID)
+	optFn := inRegion(region)
+
+	api, err := a.client.GetGraphqlApi(ctx, &appsync_sdk.GetGraphqlApiInput{ApiId: id}, optFn)
+	if err != nil {
+		return nil, err
+	}
+	export := &APIExport{API: newGraphqlAPI(*api.GraphqlApi)}
+
+	schema, err := a.client.GetIntrospectionSchema(ctx, &appsync_sdk.GetIntrospectionSchemaInput{
+		ApiId:             id,
+		Format:            types.OutputTypeSdl,
+		IncludeDirectives: aws_sdk.Bool(true),
+	}, optFn)
+	if err != nil {
+		return nil, err
+	}
+	export.Schema = string(schema.Schema)
+
+	dataSourcesInput := &appsync_sdk.ListDataSourcesInput{ApiId: id}
+	for {
+		result, err := a.client.ListDataSources(ctx, dataSourcesInput, optFn)
+		if err != nil {
+			return nil, err
+		}
+		for _, ds := range result.DataSources {
+			export.DataSources = append(export.DataSources, newDataSource(ds))
+		}
+		if result.NextToken == nil {
+			break
+		}
+		dataSourcesInput.NextToken = result.NextToken
+	}
+
+	functionsInput := &appsync_sdk.ListFunctionsInput{ApiId: id}
+	for {
+		result, err := a.client.ListFunctions(ctx, functionsInput, optFn)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range result.Functions {
+			export.Functions = append(export.Functions, newFunction(f))
+		}
+		if result.NextToken == nil {
+			break
+		}
+		functionsInput.NextToken = result.NextToken
+	}
+
+	typesInput := &appsync_sdk.ListTypesInput{ApiId: id, Format: types.TypeDefinitionFormatSdl}
+	for {
+		result, err := a.client.ListTypes(ctx, typesInput, optFn)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range result.Types {
+			resolvers, err := a.listResolvers(ctx, region, id, t.Name)
+			if err != nil {
+				return nil, err
+			}
+			export.Resolvers = append(export.Resolvers, resolvers...)
+		}
+		if result.NextToken == nil {
+			break
+		}
+		typesInput.NextToken = result.NextToken
+	}
+
+	return export, nil
+}
+
+// listResolvers lists the resolvers of a single type
+func (a appsync) listResolvers(ctx context.Context, region string, apiID *string, typeName *string) ([]Resolver, error) {
+	var resolvers []Resolver
+
+	input := &appsync_sdk.ListResolversInput{ApiId: apiID, TypeName: typeName}
+	for {
+		result, err := a.client.ListResolvers(ctx, input, inRegion(region))
+		if err != nil {
+			return resolvers, err
+		}
+		for _, r := range result.Resolvers {
+			resolvers = append(resolvers, newResolver(r))
+		}
+		if result.NextToken == nil {
+			return resolvers, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+func newGraphqlAPI(api types.GraphqlApi) GraphqlAPI {
+	g := GraphqlAPI{
+		ID:                 aws_sdk.ToString(api.ApiId),
+		Name:               aws_sdk.ToString(api.Name),
+		ARN:                aws_sdk.ToString(api.Arn),
+		AuthenticationType: string(api.AuthenticationType),
+		XRayEnabled:        api.XrayEnabled,
+		Tags:               api.Tags,
+	}
+
+	if c := api.LambdaAuthorizerConfig; c != nil {
+		g.AuthorizerURI = aws_sdk.ToString(c.AuthorizerUri)
+		g.AuthorizerResultTTLInSeconds = int64(c.AuthorizerResultTtlInSeconds)
+		g.IdentityValidationExpression = aws_sdk.ToString(c.IdentityValidationExpression)
+	}
+	if c := api.UserPoolConfig; c != nil {
+		g.UserPoolID = aws_sdk.ToString(c.UserPoolId)
+		g.UserPoolRegion = aws_sdk.ToString(c.AwsRegion)
+		g.UserPoolDefaultAction = string(c.DefaultAction)
+		g.AppIDClientRegex = aws_sdk.ToString(c.AppIdClientRegex)
+	}
+	if c := api.OpenIDConnectConfig; c != nil {
+		g.OIDCIssuer = aws_sdk.ToString(c.Issuer)
+		g.OIDCClientID = aws_sdk.ToString(c.ClientId)
+	}
+	if c := api.LogConfig; c != nil {
+		g.LogRoleARN = aws_sdk.ToString(c.CloudWatchLogsRoleArn)
+		g.FieldLogLevel = string(c.FieldLogLevel)
+		g.ExcludeVerboseContent = c.ExcludeVerboseContent
+	}
+	for _, p := range api.AdditionalAuthenticationProviders {
+		g.AdditionalAuthenticationProviders = append(g.AdditionalAuthenticationProviders, newAuthenticationProvider(p))
+	}
+
+	return g
+}
+
+func newAuthenticationProvider(p types.AdditionalAuthenticationProvider) AuthenticationProvider {
+	a := AuthenticationProvider{AuthenticationType: string(p.AuthenticationType)}
+
+	if c := p.LambdaAuthorizerConfig; c != nil {
+		a.AuthorizerURI = aws_sdk.ToString(c.AuthorizerUri)
+		a.AuthorizerResultTTLInSeconds = int64(c.AuthorizerResultTtlInSeconds)
+		a.IdentityValidationExpression = aws_sdk.ToString(c.IdentityValidationExpression)
+	}
+	if c := p.UserPoolConfig; c != nil {
+		a.UserPoolID = aws_sdk.ToString(c.UserPoolId)
+		a.UserPoolRegion = aws_sdk.ToString(c.AwsRegion)
+		a.AppIDClientRegex = aws_sdk.ToString(c.AppIdClientRegex)
+	}
+	if c := p.OpenIDConnectConfig; c != nil {
+		a.OIDCIssuer = aws_sdk.ToString(c.Issuer)
+		a.OIDCClientID = aws_sdk.ToString(c.ClientId)
+	}
+
+	return a
+}
+
+func newDataSource(ds types.DataSource) DataSource {
+	d := DataSource{
+		Name:           aws_sdk.ToString(ds.Name),
+		Type:           string(ds.Type),
+		Description:    aws_sdk.ToString(ds.Description),
+		ServiceRoleARN: aws_sdk.ToString(ds.ServiceRoleArn),
+	}
+
+	if c := ds.LambdaConfig; c != nil {
+		d.LambdaFunctionARN = aws_sdk.ToString(c.LambdaFunctionArn)
+	}
+	if c := ds.DynamodbConfig; c != nil {
+		d.TableName = aws_sdk.ToString(c.TableName)
+		d.TableRegion = aws_sdk.ToString(c.AwsRegion)
+	}
+	if c := ds.HttpConfig; c != nil {
+		d.HTTPEndpoint = aws_sdk.ToString(c.Endpoint)
+	}
+	if c := ds.EventBridgeConfig; c != nil {
+		d.EventBusARN = aws_sdk.ToString(c.EventBusArn)
+	}
+	if c := ds.RelationalDatabaseConfig; c != nil && c.RdsHttpEndpointConfig != nil {
+		d.DBClusterIdentifier = aws_sdk.ToString(c.RdsHttpEndpointConfig.DbClusterIdentifier)
+		d.DatabaseName = aws_sdk.ToString(c.RdsHttpEndpointConfig.DatabaseName)
+		d.DatabaseSchema = aws_sdk.ToString(c.RdsHttpEndpointConfig.Schema)
+		d.DatabaseRegion = aws_sdk.ToString(c.RdsHttpEndpointConfig.AwsRegion)
+		d.SecretStoreARN = aws_sdk.ToString(c.RdsHttpEndpointConfig.AwsSecretStoreArn)
+	}
+	if c := ds.OpenSearchServiceConfig; c != nil {
+		d.SearchEndpoint = aws_sdk.ToString(c.Endpoint)
+		d.SearchRegion = aws_sdk.ToString(c.AwsRegion)
+	}
+	if c := ds.ElasticsearchConfig; c != nil {
+		d.SearchEndpoint = aws_sdk.ToString(c.Endpoint)
+		d.SearchRegion = aws_sdk.ToString(c.AwsRegion)
+	}
+
+	return d
+}
+
+func newResolver(r types.Resolver) Resolver {
+	resolver := Resolver{
+		TypeName:         aws_sdk.ToString(r.TypeName),
+		FieldName:        aws_sdk.ToString(r.FieldName),
+		Kind:             string(r.Kind),
+		DataSourceName:   aws_sdk.ToString(r.DataSourceName),
+		Code:             aws_sdk.ToString(r.Code),
+		RequestTemplate:  aws_sdk.ToString(r.RequestMappingTemplate),
+		ResponseTemplate: aws_sdk.ToString(r.ResponseMappingTemplate),
+	}
+
+	if r.Runtime != nil {
+		resolver.RuntimeName = string(r.Runtime.Name)
+		resolver.RuntimeVersion = aws_sdk.ToString(r.Runtime.RuntimeVersion)
+	}
+	if r.PipelineConfig != nil {
+		resolver.Functions = r.PipelineConfig.Functions
+	}
+
+	return resolver
+}
+
+func newFunction(f types.FunctionConfiguration) Function {
+	function := Function{
+		ID:               aws_sdk.ToString(f.FunctionId),
+		Name:             aws_sdk.ToString(f.Name),
+		Description:      aws_sdk.ToString(f.Description),
+		DataSourceName:   aws_sdk.ToString(f.DataSourceName),
+		Code:             aws_sdk.ToString(f.Code),
+		FunctionVersion:  aws_sdk.ToString(f.FunctionVersion),
+		RequestTemplate:  aws_sdk.ToString(f.RequestMappingTemplate),
+		ResponseTemplate: aws_sdk.ToString(f.ResponseMappingTemplate),
+	}
+
+	if f.Runtime != nil {
+		function.RuntimeName = string(f.Runtime.Name)
+		function.RuntimeVersion = aws_sdk.ToString(f.Runtime.RuntimeVersion)
+	}
+
+	return function
 }
@@ -1,48 +1,230 @@
 package aws
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws/endpoints"
-	"github.com/aws/aws-sdk-go/aws/session"
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type aws struct {
-	Sess *session.Session
+	cfg                aws_sdk.Config
+	profile            string
+	enabledRegionsOnly bool
+
+	mu sync.Mutex
+	// identity is known once STS answered
+	identity *Identity
 }
 
 type AWS interface {
-	GetSession() *session.Session
-	Regions(service string) ([]string, error)
+	Caller
+	Config() aws_sdk.Config
+	Partition(ctx context.Context) string
+	Regions(ctx context.Context, service string) ([]string, error)
+}
+
+// Caller tells whose credentials the requests are signed with, the interactive mode shows it
+type Caller interface {
+	Profile() string
+	Region() string
+	Identity(ctx context.Context) (Identity, error)
+}
+
+// Identity is the caller of the requests
+type Identity struct {
+	Account   string
+	ARN       string
+	UserID    string
+	Partition string
+}
+
+type options struct {
+	profile            string
+	region             string
+	maxAttempts        int
+	timeout            time.Duration
+	enabledRegionsOnly bool
+}
+
+type Option func(*options)
+
+// WithProfile selects a profile of the shared configuration instead of $AWS_PROFILE or the default one,
+// SSO and credential_process profiles are supported
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
+
+// WithRegion overrides the region of the profile
+func WithRegion(region string) Option {
+	return func(o *options) {
+		o.region = region
+	}
+}
+
+// WithMaxAttempts sets how many times a failing request is attempted, including the first attempt
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(o *options) {
+		o.maxAttempts = maxAttempts
+	}
 }
 
-// NewAWS returns a new AWS interface
-func NewAWS() AWS {
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}))
-	return aws{
-		Sess: sess,
+// WithTimeout limits the duration of every HTTP request, each attempt gets the whole timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
 	}
 }
 
-// GetSession returns the session
-func (a aws) GetSession() *session.Session {
-	return a.Sess
+// WithEnabledRegionsOnly hides the regions not enabled for the account, like the opt-in regions
+// nobody enabled, it needs the ec2:DescribeRegions permission
+func WithEnabledRegionsOnly(enabledRegionsOnly bool) Option {
+	return func(o *options) {
+		o.enabledRegionsOnly = enabledRegionsOnly
+	}
 }
 
-// Regions returns a list of regions that the given service is
-func (a aws) Regions(service string) ([]string, error) {
-	sr, exists := endpoints.RegionsForService(endpoints.DefaultPartitions(), endpoints.AwsPartitionID, service)
+// NewAWS loads the shared configuration and the credentials, the zero options keep the defaults of the SDK
+func NewAWS(ctx context.Context, opts ...Option) (AWS, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var loadOptions []func(*config.LoadOptions) error
+	if o.profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(o.profile))
+	}
+	if o.region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(o.region))
+	}
+	if o.maxAttempts > 0 {
+		loadOptions = append(loadOptions, config.WithRetryMaxAttempts(o.maxAttempts))
+	}
+	if o.timeout > 0 {
+		loadOptions = append(loadOptions, config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(o.timeout)))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the aws configuration: %w", err)
+	}
+
+	profile := o.profile
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	return &aws{
+		cfg:                cfg,
+		profile:            profile,
+		enabledRegionsOnly: o.enabledRegionsOnly,
+	}, nil
+}
+
+// Config returns the configuration the clients are created from
+func (a *aws) Config() aws_sdk.Config {
+	return a.cfg
+}
+
+// Profile returns the name of the profile of the shared configuration the credentials come from
+func (a *aws) Profile() string {
+	return a.profile
+}
+
+// Region returns the region the requests are sent to by default
+func (a *aws) Region() string {
+	return a.cfg.Region
+}
+
+// Identity asks STS who the credentials belong to, the answer is kept for the next calls
+func (a *aws) Identity(ctx context.Context) (Identity, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.identity != nil {
+		return *a.identity, nil
+	}
+
+	result, err := sts.NewFromConfig(a.cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return Identity{}, err
+	}
+	caller, err := arn.Parse(aws_sdk.ToString(result.Arn))
+	if err != nil {
+		return Identity{}, err
+	}
+
+	a.identity = &Identity{
+		Account:   aws_sdk.ToString(result.Account),
+		ARN:       aws_sdk.ToString(result.Arn),
+		UserID:    aws_sdk.ToString(result.UserId),
+		Partition: caller.Partition,
+	}
+	return *a.identity, nil
+}
+
+// Partition returns the partition of the account, like aws-us-gov, from the ARN of the caller.
+// The region of the configuration tells the partition when STS can not be reached.
+func (a *aws) Partition(ctx context.Context) string {
+	identity, err := a.Identity(ctx)
+	if err != nil {
+		return partitionOf(a.cfg.Region)
+	}
+	return identity.Partition
+}
+
+// Regions returns a list of regions of the partition of the account that the given service is
+func (a *aws) Regions(ctx context.Context, service string) ([]string, error) {
+	byPartition, exists := serviceRegions[service]
 	if !exists {
 		return nil, fmt.Errorf("service %s does not exist", service)
 	}
 
-	regions := make([]string, 0, len(sr))
-	for _, region := range sr {
-		r := region.ID()
-		regions = append(regions, r)
+	partition := a.Partition(ctx)
+	sr, exists := byPartition[partition]
+	if !exists {
+		sr = partitions[partition].regions
+	}
+
+	regions := slices.Clone(sr)
+	if !a.enabledRegionsOnly {
+		return regions, nil
+	}
+
+	enabled, err := a.enabledRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the regions enabled for the account: %w", err)
 	}
+	return slices.DeleteFunc(regions, func(region string) bool {
+		return !slices.Contains(enabled, region)
+	}), nil
+}
 
+// enabledRegions lists the regions enabled for the account
+func (a *aws) enabledRegions(ctx context.Context) ([]string, error) {
+	result, err := ec2.NewFromConfig(a.cfg).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	regions := make([]string, 0, len(result.Regions))
+	for _, r := range result.Regions {
+		regions = append(regions, aws_sdk.ToString(r.RegionName))
+	}
 	return regions, nil
 }
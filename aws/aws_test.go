@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sharedConfig points the SDK at a configuration file with a default and a named profile
+func sharedConfig(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	content := "[default]\nregion = us-east-1\n\n[profile dev]\nregion = eu-central-1\nmax_attempts = 7\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("AWS_CONFIG_FILE", path)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	for _, name := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_MAX_ATTEMPTS"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestNewAWS(t *testing.T) {
+	sharedConfig(t)
+	ctx := context.Background()
+
+	a, err := NewAWS(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if region := a.Region(); region != "us-east-1" {
+		t.Errorf("expected the region of the default profile, got %s", region)
+	}
+	if profile := a.Profile(); profile != "default" {
+		t.Errorf("expected the default profile, got %s", profile)
+	}
+
+	a, err = NewAWS(ctx, WithProfile("dev"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if region := a.Config().Region; region != "eu-central-1" {
+		t.Errorf("expected the region of the dev profile, got %s", region)
+	}
+	if profile := a.Profile(); profile != "dev" {
+		t.Errorf("expected the dev profile, got %s", profile)
+	}
+	if attempts := a.Config().RetryMaxAttempts; attempts != 7 {
+		t.Errorf("expected the attempts of the dev profile, got %d", attempts)
+	}
+
+	a, err = NewAWS(ctx, WithProfile("dev"), WithRegion("eu-west-1"), WithMaxAttempts(2), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := a.Config()
+	if a.Region() != "eu-west-1" || cfg.RetryMaxAttempts != 2 {
+		t.Errorf("expected the options to override the profile, got %s and %d", cfg.Region, cfg.RetryMaxAttempts)
+	}
+	if client, ok := cfg.HTTPClient.(interface{ GetTimeout() time.Duration }); !ok || client.GetTimeout() != time.Second {
+		t.Errorf("expected a client with a timeout, got %T", cfg.HTTPClient)
+	}
+
+	if _, err := NewAWS(ctx, WithProfile("missing")); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+}
+
+func TestRegions(t *testing.T) {
+	tcs := []struct {
+		partition string
+		want      []string
+	}{
+		{partition: "aws-cn", want: []string{"cn-north-1", "cn-northwest-1"}},
+		{partition: "aws-us-gov", want: []string{"us-gov-east-1", "us-gov-west-1"}},
+		{partition: "aws-iso-b", want: []string{"us-isob-east-1"}},
+	}
+	for _, tc := range tcs {
+		// the partition is known, STS is not called
+		a := &aws{identity: &Identity{Partition: tc.partition}}
+		regions, err := a.Regions(context.Background(), "appsync")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(regions, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.partition, tc.want, regions)
+		}
+	}
+
+	a := &aws{identity: &Identity{Partition: defaultPartition}}
+	regions, err := a.Regions(context.Background(), "appsync")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Contains(regions, "eu-central-1") || slices.Contains(regions, "cn-north-1") {
+		t.Errorf("expected the commercial regions, got %v", regions)
+	}
+
+	regions[0] = "changed"
+	if serviceRegions["appsync"][defaultPartition][0] == "changed" {
+		t.Error("expected a copy of the regions")
+	}
+
+	if _, err := a.Regions(context.Background(), "unknown"); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+}
+
+func TestPartitionOf(t *testing.T) {
+	tcs := map[string]string{
+		"eu-central-1":   "aws",
+		"":               "aws",
+		"cn-northwest-1": "aws-cn",
+		"us-gov-west-1":  "aws-us-gov",
+		"us-iso-east-1":  "aws-iso",
+		"us-isob-east-1": "aws-iso-b",
+		"eu-isoe-west-1": "aws-iso-e",
+		"us-isof-east-1": "aws-iso-f",
+	}
+	for region, want := range tcs {
+		if got := partitionOf(region); got != want {
+			t.Errorf("%s: expected %s, got %s", region, want, got)
+		}
+	}
+}
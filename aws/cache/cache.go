@@ -0,0 +1,212 @@
+// Package cache keeps the results of the AWS lookups of the forms on disk, so the lists open instantly
+// in the next sessions. Expired results are still served while Revalidate lists the resources again.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long a lookup is served without being revalidated
+const DefaultTTL = 24 * time.Hour
+
+// Scope separates the lookups of different credentials, the lists of an account differ between regions
+type Scope struct {
+	Account string
+	Profile string
+	Region  string
+}
+
+// fileName returns a file name for the scope, the parts are escaped as profiles may contain any character
+func (s Scope) fileName() string {
+	return url.PathEscape(s.Account) + "_" + url.PathEscape(s.Profile) + "_" + url.PathEscape(s.Region) + ".json"
+}
+
+type entry struct {
+	Value   json.RawMessage `json:"value"`
+	Updated time.Time       `json:"updated"`
+}
+
+// lookupFunc lists resources again, its result is stored as JSON
+type lookupFunc func(ctx context.Context) (json.RawMessage, error)
+
+// Cache stores the lookups of a scope in a file. A nil Cache caches nothing.
+type Cache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+	// lookups are the lookups served in this session, Revalidate runs them again
+	lookups map[string]lookupFunc
+	// stale are the keys served from an expired entry since the last revalidation
+	stale map[string]bool
+}
+
+// DefaultDir returns the directory of the cache files, in $XDG_CACHE_HOME or ~/.cache
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "terrapi", "aws"), nil
+}
+
+// Open reads the lookups of the scope stored in dir, a missing or unreadable file gives an empty cache
+func Open(dir string, scope Scope, ttl time.Duration) *Cache {
+	c := &Cache{
+		path:    filepath.Join(dir, scope.fileName()),
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]entry{},
+		lookups: map[string]lookupFunc{},
+		stale:   map[string]bool{},
+	}
+
+	if data, err := os.ReadFile(c.path); err == nil {
+		if err := json.Unmarshal(data, &c.entries); err != nil {
+			c.entries = map[string]entry{}
+		}
+	}
+
+	return c
+}
+
+// lookup serves the stored result of key, fetch lists the resources when there is none.
+// An expired result is served and marked stale.
+func lookup[T any](ctx context.Context, c *Cache, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	c.lookups[key] = func(ctx context.Context) (json.RawMessage, error) {
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	}
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	var value T
+	if ok && json.Unmarshal(e.Value, &value) == nil {
+		if c.now().Sub(e.Updated) >= c.ttl {
+			c.mu.Lock()
+			c.stale[key] = true
+			c.mu.Unlock()
+		}
+		return value, nil
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{Value: data, Updated: c.now()}
+	// the resources were listed, a cache which can not be written only makes the next session slower
+	c.save()
+	return value, nil
+}
+
+// Stale reports whether a lookup was served from an expired result since the last revalidation
+func (c *Cache) Stale() bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.stale) > 0
+}
+
+// Expire marks the lookups served in this session stale, the next revalidation lists them all again
+func (c *Cache) Expire() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.lookups {
+		c.stale[key] = true
+	}
+}
+
+// Revalidate runs the stale lookups again and stores their results. The results of the failing lookups
+// are kept, they are revalidated again next time.
+func (c *Cache) Revalidate(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	lookups := map[string]lookupFunc{}
+	for key := range c.stale {
+		lookups[key] = c.lookups[key]
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	results := map[string]json.RawMessage{}
+	for key, lookup := range lookups {
+		value, err := lookup(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[key] = value
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, value := range results {
+		c.entries[key] = entry{Value: value, Updated: c.now()}
+		delete(c.stale, key)
+	}
+	if len(results) > 0 {
+		errs = append(errs, c.save())
+	}
+	return errors.Join(errs...)
+}
+
+// save writes the entries, the file is replaced at once so a concurrent session never reads half of it
+func (c *Cache) save() error {
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xsevy/terrapi/aws/fake"
+)
+
+// counter lists the items it holds and counts the calls
+type counter struct {
+	items []string
+	err   error
+	calls int
+}
+
+func (c *counter) list(ctx context.Context) ([]string, error) {
+	c.calls++
+	return c.items, c.err
+}
+
+// clock is the time of a cache, the tests move it forward
+type clock struct {
+	now time.Time
+}
+
+func open(t *testing.T, dir string, clk *clock) *Cache {
+	t.Helper()
+	c := Open(dir, Scope{Account: "123456789012", Profile: "dev", Region: "eu-central-1"}, time.Hour)
+	c.now = func() time.Time { return clk.now }
+	return c
+}
+
+func TestLookupAcrossSessions(t *testing.T) {
+	dir := t.TempDir()
+	clk := &clock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	ctx := context.Background()
+	functions := &counter{items: []string{"authorizer"}}
+
+	c := open(t, dir, clk)
+	for i := 0; i < 2; i++ {
+		items, err := lookup(ctx, c, "lambda/functions", functions.list)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(items, []string{"authorizer"}) {
+			t.Errorf("unexpected items %v", items)
+		}
+	}
+	if functions.calls != 1 {
+		t.Errorf("expected a single call, got %d", functions.calls)
+	}
+
+	// the next session serves the stored result
+	functions.items = []string{"authorizer", "users"}
+	c = open(t, dir, clk)
+	items, _ := lookup(ctx, c, "lambda/functions", functions.list)
+	if !reflect.DeepEqual(items, []string{"authorizer"}) || functions.calls != 1 {
+		t.Errorf("expected the stored result, got %v after %d calls", items, functions.calls)
+	}
+	if c.Stale() {
+		t.Error("expected a fresh result")
+	}
+
+	// another scope has its own results
+	other := Open(dir, Scope{Account: "123456789012", Profile: "prod", Region: "eu-central-1"}, time.Hour)
+	if items, _ := lookup(ctx, other, "lambda/functions", functions.list); len(items) != 2 {
+		t.Errorf("expected the functions to be listed for another profile, got %v", items)
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	dir := t.TempDir()
+	clk := &clock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	ctx := context.Background()
+	buckets := &counter{items: []string{"states"}}
+
+	lookup(ctx, open(t, dir, clk), "s3/buckets", buckets.list)
+
+	clk.now = clk.now.Add(2 * time.Hour)
+	buckets.items = []string{"artifacts", "states"}
+	c := open(t, dir, clk)
+	items, _ := lookup(ctx, c, "s3/buckets", buckets.list)
+	if !reflect.DeepEqual(items, []string{"states"}) {
+		t.Errorf("expected the expired result to be served, got %v", items)
+	}
+	if !c.Stale() {
+		t.Fatal("expected the expired result to be stale")
+	}
+
+	// a failing revalidation keeps the result
+	buckets.err = errors.New("expired token")
+	if err := c.Revalidate(ctx); err == nil {
+		t.Error("expected the error of the lookup")
+	}
+	if !c.Stale() {
+		t.Error("expected the result to stay stale")
+	}
+
+	buckets.err = nil
+	if err := c.Revalidate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Stale() {
+		t.Error("expected the result to be fresh")
+	}
+	items, _ = lookup(ctx, c, "s3/buckets", buckets.list)
+	if !reflect.DeepEqual(items, []string{"artifacts", "states"}) || buckets.calls != 3 {
+		t.Errorf("expected the revalidated result, got %v after %d calls", items, buckets.calls)
+	}
+}
+
+func TestExpire(t *testing.T) {
+	clk := &clock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	ctx := context.Background()
+	tables := &counter{items: []string{"locks"}}
+
+	c := open(t, t.TempDir(), clk)
+	lookup(ctx, c, "dynamodb/tables", tables.list)
+
+	c.Expire()
+	if !c.Stale() {
+		t.Fatal("expected the served lookups to be stale")
+	}
+	if err := c.Revalidate(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tables.calls != 2 {
+		t.Errorf("expected the tables to be listed again, got %d calls", tables.calls)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	ctx := context.Background()
+	functions := &counter{items: []string{"authorizer"}}
+
+	lookup(ctx, c, "lambda/functions", functions.list)
+	lookup(ctx, c, "lambda/functions", functions.list)
+	c.Expire()
+	if c.Stale() || c.Revalidate(ctx) != nil {
+		t.Error("expected a nil cache to have nothing to revalidate")
+	}
+	if functions.calls != 2 {
+		t.Errorf("expected every lookup to list the functions, got %d calls", functions.calls)
+	}
+}
+
+func TestClients(t *testing.T) {
+	ctx := context.Background()
+	account := fake.New(fake.Fixture{
+		Regions: []string{"eu-central-1"},
+		Buckets: map[string][]string{"states": {"blog/terraform.tfstate", "notes.txt"}},
+		Tables:  []string{"locks"},
+	})
+	c := open(t, t.TempDir(), &clock{now: time.Now()})
+
+	states, err := c.S3(account).ListStates(ctx, "states")
+	if err != nil || !reflect.DeepEqual(states, []string{"blog/terraform.tfstate"}) {
+		t.Errorf("unexpected states %v, %v", states, err)
+	}
+	if _, err := c.S3(account).ListStates(ctx, "missing"); err == nil {
+		t.Error("expected the error of the client")
+	}
+	regions, _ := c.AppSync(account).Regions(ctx)
+	tables, _ := c.DynamoDB(account).ListTables(ctx)
+	if !reflect.DeepEqual(regions, []string{"eu-central-1"}) || !reflect.DeepEqual(tables, []string{"locks"}) {
+		t.Errorf("unexpected regions %v or tables %v", regions, tables)
+	}
+
+	for _, key := range []string{"s3/states/states", "appsync/regions", "dynamodb/tables"} {
+		if _, ok := c.entries[key]; !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+	if _, ok := c.entries["s3/states/missing"]; ok {
+		t.Error("expected a failing lookup not to be cached")
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/xsevy/terrapi/aws"
+)
+
+// the lookups of the forms are cached, the lookups of the drift report always reach AWS
+type lambda struct {
+	aws.Lambda
+	cache *Cache
+}
+
+type appsync struct {
+	aws.AppSync
+	cache *Cache
+}
+
+type s3 struct {
+	aws.S3
+	cache *Cache
+}
+
+type dynamoDB struct {
+	aws.DynamoDB
+	cache *Cache
+}
+
+// Lambda caches the functions listed by client
+func (c *Cache) Lambda(client aws.Lambda) aws.Lambda {
+	return lambda{Lambda: client, cache: c}
+}
+
+// AppSync caches the regions and the APIs listed by client
+func (c *Cache) AppSync(client aws.AppSync) aws.AppSync {
+	return appsync{AppSync: client, cache: c}
+}
+
+// S3 caches the buckets and the states listed by client
+func (c *Cache) S3(client aws.S3) aws.S3 {
+	return s3{S3: client, cache: c}
+}
+
+// DynamoDB caches the tables listed by client
+func (c *Cache) DynamoDB(client aws.DynamoDB) aws.DynamoDB {
+	return dynamoDB{DynamoDB: client, cache: c}
+}
+
+func (l lambda) ListFunctions(ctx context.Context) ([]string, error) {
+	return lookup(ctx, l.cache, "lambda/functions", l.Lambda.ListFunctions)
+}
+
+func (a appsync) Regions(ctx context.Context) ([]string, error) {
+	return lookup(ctx, a.cache, "appsync/regions", a.AppSync.Regions)
+}
+
+func (a appsync) ListAPIs(ctx context.Context, region string) ([]aws.GraphqlAPI, error) {
+	return lookup(ctx, a.cache, "appsync/apis/"+region, func(ctx context.Context) ([]aws.GraphqlAPI, error) {
+		return a.AppSync.ListAPIs(ctx, region)
+	})
+}
+
+func (s s3) ListBuckets(ctx context.Context) ([]string, error) {
+	return lookup(ctx, s.cache, "s3/buckets", s.S3.ListBuckets)
+}
+
+func (s s3) ListStates(ctx context.Context, bucket string) ([]string, error) {
+	return lookup(ctx, s.cache, "s3/states/"+bucket, func(ctx context.Context) ([]string, error) {
+		return s.S3.ListStates(ctx, bucket)
+	})
+}
+
+func (d dynamoDB) ListTables(ctx context.Context) ([]string, error) {
+	return lookup(ctx, d.cache, "dynamodb/tables", d.DynamoDB.ListTables)
+}
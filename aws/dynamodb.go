@@ -1,33 +1,35 @@
 package aws
 
 import (
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
 type dynamoDB struct {
-	client *dynamodb.DynamoDB
+	client *dynamodb.Client
 }
 
 type DynamoDB interface {
-	ListTables() ([]string, error)
+	ListTables(ctx context.Context) ([]string, error)
 }
 
 func NewDynamoDB(aws AWS) DynamoDB {
 	return &dynamoDB{
-		client: dynamodb.New(aws.GetSession()),
+		client: dynamodb.NewFromConfig(aws.Config()),
 	}
 }
 
-func (d *dynamoDB) ListTables() ([]string, error) {
+func (d *dynamoDB) ListTables(ctx context.Context) ([]string, error) {
 	var tables []string
 
-	result, err := d.client.ListTables(nil)
-	if err != nil {
-		return tables, err
-	}
-
-	for _, t := range result.TableNames {
-		tables = append(tables, *t)
+	paginator := dynamodb.NewListTablesPaginator(d.client, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return tables, err
+		}
+		tables = append(tables, page.TableNames...)
 	}
 
 	return tables, nil
@@ -0,0 +1,146 @@
+// Package fake implements the aws interfaces in memory, for the tests and the demo mode
+package fake
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xsevy/terrapi/aws"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed demo.yaml
+var demoFixture []byte
+
+// AWS serves the resources of a fixture, it implements the Lambda, AppSync, S3, DynamoDB, IAM and Caller interfaces
+type AWS struct {
+	fixture Fixture
+}
+
+var (
+	_ aws.Lambda   = (*AWS)(nil)
+	_ aws.AppSync  = (*AWS)(nil)
+	_ aws.S3       = (*AWS)(nil)
+	_ aws.DynamoDB = (*AWS)(nil)
+	_ aws.IAM      = (*AWS)(nil)
+	_ aws.Caller   = (*AWS)(nil)
+)
+
+// New returns a fake account holding the resources of fixture
+func New(fixture Fixture) *AWS {
+	return &AWS{fixture: fixture}
+}
+
+// Load reads a fixture file, see demo.yaml for its format
+func Load(path string) (*AWS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse reads a fixture
+func Parse(data []byte) (*AWS, error) {
+	var fixture Fixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("unable to read the fixture: %w", err)
+	}
+	return New(fixture), nil
+}
+
+// Demo returns the account of the demo mode
+func Demo() *AWS {
+	a, err := Parse(demoFixture)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a *AWS) ListFunctions(ctx context.Context) ([]string, error) {
+	return slices.Clone(a.fixture.Functions), nil
+}
+
+func (a *AWS) ListRuntimes(ctx context.Context) ([]string, error) {
+	return slices.Clone(a.fixture.Runtimes), nil
+}
+
+func (a *AWS) FunctionExists(ctx context.Context, region string, name string) (bool, error) {
+	return slices.Contains(a.fixture.Functions, name), nil
+}
+
+func (a *AWS) Regions(ctx context.Context) ([]string, error) {
+	return slices.Clone(a.fixture.Regions), nil
+}
+
+func (a *AWS) ListAPIs(ctx context.Context, region string) ([]aws.GraphqlAPI, error) {
+	var apis []aws.GraphqlAPI
+	for _, api := range a.fixture.APIs[region] {
+		apis = append(apis, api.graphqlAPI(region))
+	}
+	return apis, nil
+}
+
+func (a *AWS) ExportAPI(ctx context.Context, region string, apiID string) (*aws.APIExport, error) {
+	for _, api := range a.fixture.APIs[region] {
+		if api.ID == apiID {
+			return api.export(region), nil
+		}
+	}
+	return nil, fmt.Errorf("api %s not found in %s", apiID, region)
+}
+
+func (a *AWS) ListBuckets(ctx context.Context) ([]string, error) {
+	buckets := make([]string, 0, len(a.fixture.Buckets))
+	for bucket := range a.fixture.Buckets {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	return buckets, nil
+}
+
+func (a *AWS) ListStates(ctx context.Context, bucket string) ([]string, error) {
+	states, ok := a.fixture.Buckets[bucket]
+	if !ok {
+		return nil, fmt.Errorf("bucket %s not found", bucket)
+	}
+
+	var keys []string
+	for _, key := range states {
+		if strings.HasSuffix(key, ".tfstate") {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (a *AWS) ListTables(ctx context.Context) ([]string, error) {
+	return slices.Clone(a.fixture.Tables), nil
+}
+
+func (a *AWS) RoleExists(ctx context.Context, name string) (bool, error) {
+	return slices.Contains(a.fixture.Roles, name), nil
+}
+
+func (a *AWS) Profile() string {
+	return a.fixture.Caller.Profile
+}
+
+func (a *AWS) Region() string {
+	return a.fixture.Caller.Region
+}
+
+func (a *AWS) Identity(ctx context.Context) (aws.Identity, error) {
+	caller := a.fixture.Caller
+	if caller.Account == "" {
+		return aws.Identity{}, errors.New("the fixture has no caller")
+	}
+	return aws.Identity{Account: caller.Account, ARN: caller.ARN, Partition: "aws"}, nil
+}
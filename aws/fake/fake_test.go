@@ -0,0 +1,106 @@
+package fake
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/templates"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.yaml")
+	content := `
+regions: [eu-central-1]
+functions: [authorizer]
+buckets:
+  states: [a/terraform.tfstate, a/notes.txt]
+roles: [role]
+apis:
+  eu-central-1:
+    - id: abc
+      name: users
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	a, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if states, _ := a.ListStates(ctx, "states"); !reflect.DeepEqual(states, []string{"a/terraform.tfstate"}) {
+		t.Errorf("expected only the terraform states, got %v", states)
+	}
+	if _, err := a.ListStates(ctx, "missing"); err == nil {
+		t.Error("expected an error for a missing bucket")
+	}
+	if exists, _ := a.FunctionExists(ctx, "us-east-1", "authorizer"); !exists {
+		t.Error("expected the functions to exist in every region")
+	}
+	if exists, _ := a.RoleExists(ctx, "other"); exists {
+		t.Error("expected an unknown role not to exist")
+	}
+	if apis, _ := a.ListAPIs(ctx, "eu-central-1"); len(apis) != 1 || apis[0].Name != "users" {
+		t.Errorf("unexpected apis %v", apis)
+	}
+	if apis, _ := a.ListAPIs(ctx, "us-east-1"); len(apis) != 0 {
+		t.Errorf("expected no apis in another region, got %v", apis)
+	}
+	if _, err := a.ExportAPI(ctx, "eu-central-1", "missing"); err == nil {
+		t.Error("expected an error for a missing api")
+	}
+	if _, err := a.Identity(ctx); err == nil {
+		t.Error("expected an unknown identity without a caller")
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing fixture")
+	}
+	if _, err := Parse([]byte("regions: {")); err == nil {
+		t.Error("expected an error for an invalid fixture")
+	}
+}
+
+// TestDemo imports the APIs of the demo fixture, so the demo mode shows a working import
+func TestDemo(t *testing.T) {
+	a := Demo()
+	ctx := context.Background()
+
+	if identity, err := a.Identity(ctx); err != nil || identity.Account != "123456789012" || a.Profile() != "demo" {
+		t.Errorf("unexpected caller %v of profile %s, %v", identity, a.Profile(), err)
+	}
+
+	regions, _ := a.Regions(ctx)
+	for _, region := range regions {
+		apis, _ := a.ListAPIs(ctx, region)
+		for _, api := range apis {
+			export, err := a.ExportAPI(ctx, region, api.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			replacements := &messages.CreateResourceMsg{
+				ProjectName:      api.Name,
+				AWSRegion:        region,
+				BackendBucket:    "acme-terraform-states",
+				BackendKey:       api.Name + "/terraform.tfstate",
+				BackendLockTable: "terraform-locks",
+				AppSyncExport:    export,
+			}
+			dest := t.TempDir()
+			if err := templates.CreateResources(helpers.ResourceIDs.ImportAppSyncAPI, dest, replacements); err != nil {
+				t.Fatalf("unable to import %s: %v", api.Name, err)
+			}
+			if _, err := os.Stat(filepath.Join(dest, api.Name, "imports.tf")); err != nil {
+				t.Errorf("expected %s to be imported: %v", api.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package fake
+
+import "github.com/xsevy/terrapi/aws"
+
+// Fixture lists the resources of the fake account, functions and roles exist in every region
+type Fixture struct {
+	// Caller is who the requests are made as, the identity is unknown without an account
+	Caller    Caller   `yaml:"caller"`
+	Regions   []string `yaml:"regions"`
+	Runtimes  []string `yaml:"runtimes"`
+	Functions []string `yaml:"functions"`
+	// Buckets maps the S3 buckets to the keys of their objects, ListStates returns the terraform states
+	Buckets map[string][]string `yaml:"buckets"`
+	Tables  []string            `yaml:"tables"`
+	Roles   []string            `yaml:"roles"`
+	// APIs are the AppSync APIs of each region
+	APIs map[string][]API `yaml:"apis"`
+}
+
+// Caller is the identity of the credentials of the fake account
+type Caller struct {
+	Account string `yaml:"account"`
+	ARN     string `yaml:"arn"`
+	Profile string `yaml:"profile"`
+	Region  string `yaml:"region"`
+}
+
+// API is an AppSync API with what ExportAPI returns
+type API struct {
+	ID                 string            `yaml:"id"`
+	Name               string            `yaml:"name"`
+	AuthenticationType string            `yaml:"authentication_type"`
+	XRayEnabled        bool              `yaml:"xray_enabled"`
+	Tags               map[string]string `yaml:"tags"`
+	Schema             string            `yaml:"schema"`
+	DataSources        []DataSource      `yaml:"data_sources"`
+	Resolvers          []Resolver        `yaml:"resolvers"`
+}
+
+// DataSource is a data source of an API
+type DataSource struct {
+	Name              string `yaml:"name"`
+	Type              string `yaml:"type"`
+	ServiceRoleARN    string `yaml:"service_role_arn"`
+	LambdaFunctionARN string `yaml:"lambda_function_arn"`
+	TableName         string `yaml:"table_name"`
+	HTTPEndpoint      string `yaml:"http_endpoint"`
+}
+
+// Resolver is a unit resolver of an API
+type Resolver struct {
+	Type       string `yaml:"type"`
+	Field      string `yaml:"field"`
+	DataSource string `yaml:"data_source"`
+	Code       string `yaml:"code"`
+}
+
+func (a API) graphqlAPI(region string) aws.GraphqlAPI {
+	return aws.GraphqlAPI{
+		ID:                 a.ID,
+		Name:               a.Name,
+		ARN:                "arn:aws:appsync:" + region + ":123456789012:apis/" + a.ID,
+		AuthenticationType: a.AuthenticationType,
+		XRayEnabled:        a.XRayEnabled,
+		Tags:               a.Tags,
+	}
+}
+
+func (a API) export(region string) *aws.APIExport {
+	export := &aws.APIExport{
+		API:    a.graphqlAPI(region),
+		Schema: a.Schema,
+	}
+	for _, d := range a.DataSources {
+		export.DataSources = append(export.DataSources, aws.DataSource{
+			Name:              d.Name,
+			Type:              d.Type,
+			ServiceRoleARN:    d.ServiceRoleARN,
+			LambdaFunctionARN: d.LambdaFunctionARN,
+			TableName:         d.TableName,
+			TableRegion:       region,
+			HTTPEndpoint:      d.HTTPEndpoint,
+		})
+	}
+	for _, r := range a.Resolvers {
+		resolver := aws.Resolver{
+			TypeName:       r.Type,
+			FieldName:      r.Field,
+			Kind:           "UNIT",
+			DataSourceName: r.DataSource,
+			Code:           r.Code,
+		}
+		if r.Code != "" {
+			resolver.RuntimeName, resolver.RuntimeVersion = "APPSYNC_JS", "1.0.0"
+		}
+		export.Resolvers = append(export.Resolvers, resolver)
+	}
+	return export
+}
@@ -0,0 +1,39 @@
+package aws
+
+import (
+	"context"
+	"errors"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	iam_sdk "github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+type iam struct {
+	client *iam_sdk.Client
+}
+
+type IAM interface {
+	RoleExists(ctx context.Context, name string) (bool, error)
+}
+
+// NewIAM creates a new IAM client
+func NewIAM(aws AWS) IAM {
+	return &iam{
+		client: iam_sdk.NewFromConfig(aws.Config()),
+	}
+}
+
+// RoleExists checks if the IAM role exists, IAM is global so no region is needed
+func (i *iam) RoleExists(ctx context.Context, name string) (bool, error) {
+	_, err := i.client.GetRole(ctx, &iam_sdk.GetRoleInput{RoleName: aws_sdk.String(name)})
+	var noSuchEntity *types.NoSuchEntityException
+	if errors.As(err, &noSuchEntity) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -1,41 +1,65 @@
 package aws
 
 import (
-	lambda_sdk "github.com/aws/aws-sdk-go/service/lambda"
+	"context"
+	"errors"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	lambda_sdk "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 )
 
 type lambda struct {
-	client *lambda_sdk.Lambda
+	client *lambda_sdk.Client
 }
 
 type Lambda interface {
-	ListFunctions() ([]string, error)
-	ListRuntimes() ([]string, error)
+	ListFunctions(ctx context.Context) ([]string, error)
+	ListRuntimes(ctx context.Context) ([]string, error)
+	FunctionExists(ctx context.Context, region string, name string) (bool, error)
 }
 
 // NewLambda creates a new Lambda client
 func NewLambda(aws AWS) Lambda {
 	return &lambda{
-		client: lambda_sdk.New(aws.GetSession()),
+		client: lambda_sdk.NewFromConfig(aws.Config()),
 	}
 }
 
 // ListFunctions lists all lambda functions
-func (l *lambda) ListFunctions() ([]string, error) {
+func (l *lambda) ListFunctions(ctx context.Context) ([]string, error) {
 	var funcs []string
 
-	result, err := l.client.ListFunctions(nil)
-	if err != nil {
-		return funcs, err
-	}
-
-	for _, f := range result.Functions {
-		funcs = append(funcs, *f.FunctionName)
+	paginator := lambda_sdk.NewListFunctionsPaginator(l.client, &lambda_sdk.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return funcs, err
+		}
+		for _, f := range page.Functions {
+			funcs = append(funcs, aws_sdk.ToString(f.FunctionName))
+		}
 	}
 
 	return funcs, nil
 }
 
-func (l *lambda) ListRuntimes() ([]string, error) {
+func (l *lambda) ListRuntimes(ctx context.Context) ([]string, error) {
 	return []string{"python3.11"}, nil
 }
+
+// FunctionExists checks if the lambda function exists in the region
+func (l *lambda) FunctionExists(ctx context.Context, region string, name string) (bool, error) {
+	_, err := l.client.GetFunction(ctx, &lambda_sdk.GetFunctionInput{FunctionName: aws_sdk.String(name)}, func(o *lambda_sdk.Options) {
+		o.Region = region
+	})
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package aws
+
+import "strings"
+
+// defaultPartition is the partition of the commercial regions
+const defaultPartition = "aws"
+
+// partition is a group of regions isolated from the others, the credentials of an account only work in its partition
+type partition struct {
+	// regionPrefix starts the names of the regions of the partition
+	regionPrefix string
+	regions      []string
+}
+
+var partitions = map[string]partition{
+	defaultPartition: {
+		regions: []string{
+			"af-south-1",
+			"ap-east-1",
+			"ap-northeast-1",
+			"ap-northeast-2",
+			"ap-northeast-3",
+			"ap-south-1",
+			"ap-south-2",
+			"ap-southeast-1",
+			"ap-southeast-2",
+			"ap-southeast-3",
+			"ap-southeast-4",
+			"ap-southeast-5",
+			"ap-southeast-7",
+			"ca-central-1",
+			"ca-west-1",
+			"eu-central-1",
+			"eu-central-2",
+			"eu-north-1",
+			"eu-south-1",
+			"eu-south-2",
+			"eu-west-1",
+			"eu-west-2",
+			"eu-west-3",
+			"il-central-1",
+			"me-central-1",
+			"me-south-1",
+			"mx-central-1",
+			"sa-east-1",
+			"us-east-1",
+			"us-east-2",
+			"us-west-1",
+			"us-west-2",
+		},
+	},
+	"aws-cn":     {regionPrefix: "cn-", regions: []string{"cn-north-1", "cn-northwest-1"}},
+	"aws-us-gov": {regionPrefix: "us-gov-", regions: []string{"us-gov-east-1", "us-gov-west-1"}},
+	"aws-iso":    {regionPrefix: "us-iso-", regions: []string{"us-iso-east-1", "us-iso-west-1"}},
+	"aws-iso-b":  {regionPrefix: "us-isob-", regions: []string{"us-isob-east-1"}},
+	"aws-iso-e":  {regionPrefix: "eu-isoe-", regions: []string{"eu-isoe-west-1"}},
+	"aws-iso-f":  {regionPrefix: "us-isof-", regions: []string{"us-isof-east-1", "us-isof-south-1"}},
+}
+
+// serviceRegions lists the regions of the services by partition, a partition missing from the list
+// of a service gets all its regions and the endpoint resolution decides
+var serviceRegions = map[string]map[string][]string{
+	"appsync": {
+		defaultPartition: {
+			"af-south-1",
+			"ap-east-1",
+			"ap-northeast-1",
+			"ap-northeast-2",
+			"ap-northeast-3",
+			"ap-south-1",
+			"ap-south-2",
+			"ap-southeast-1",
+			"ap-southeast-2",
+			"ap-southeast-3",
+			"ap-southeast-4",
+			"ap-southeast-5",
+			"ap-southeast-7",
+			"ca-central-1",
+			"ca-west-1",
+			"eu-central-1",
+			"eu-central-2",
+			"eu-north-1",
+			"eu-south-1",
+			"eu-south-2",
+			"eu-west-1",
+			"eu-west-2",
+			"eu-west-3",
+			"il-central-1",
+			"me-central-1",
+			"me-south-1",
+			"sa-east-1",
+			"us-east-1",
+			"us-east-2",
+			"us-west-1",
+			"us-west-2",
+		},
+		"aws-cn": {"cn-north-1", "cn-northwest-1"},
+	},
+}
+
+// partitionOf returns the partition of the region, the regions of other partitions have a distinct prefix
+func partitionOf(region string) string {
+	for id, p := range partitions {
+		if p.regionPrefix != "" && strings.HasPrefix(region, p.regionPrefix) {
+			return id
+		}
+	}
+	return defaultPartition
+}
@@ -1,34 +1,74 @@
 package aws
 
 import (
-	s3_sdk "github.com/aws/aws-sdk-go/service/s3"
+	"context"
+	"strings"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3_sdk "github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
+const stateFileSuffix = ".tfstate"
+
+// maxStatePages limits the listing of a bucket to its first 10000 objects, a bucket shared with other data
+// would take long to list
+const maxStatePages = 10
+
 type s3 struct {
-	client *s3_sdk.S3
+	client *s3_sdk.Client
 }
 
 type S3 interface {
-	ListBuckets() ([]string, error)
+	ListBuckets(ctx context.Context) ([]string, error)
+	ListStates(ctx context.Context, bucket string) ([]string, error)
 }
 
 func NewS3(aws AWS) S3 {
 	return &s3{
-		client: s3_sdk.New(aws.GetSession()),
+		client: s3_sdk.NewFromConfig(aws.Config()),
 	}
 }
 
-func (s *s3) ListBuckets() ([]string, error) {
+func (s *s3) ListBuckets(ctx context.Context) ([]string, error) {
 	var buckets []string
 
-	result, err := s.client.ListBuckets(nil)
+	result, err := s.client.ListBuckets(ctx, &s3_sdk.ListBucketsInput{})
 	if err != nil {
 		return buckets, err
 	}
 
 	for _, b := range result.Buckets {
-		buckets = append(buckets, *b.Name)
+		buckets = append(buckets, aws_sdk.ToString(b.Name))
 	}
 
 	return buckets, nil
 }
+
+// ListStates lists keys of terraform state files stored in the bucket, including workspace states.
+// Only the first maxStatePages pages of the bucket are listed.
+func (s *s3) ListStates(ctx context.Context, bucket string) ([]string, error) {
+	var states []string
+
+	region, err := manager.GetBucketRegion(ctx, s.client, bucket)
+	if err != nil {
+		return states, err
+	}
+
+	paginator := s3_sdk.NewListObjectsV2Paginator(s.client, &s3_sdk.ListObjectsV2Input{Bucket: aws_sdk.String(bucket)})
+	for pages := 0; paginator.HasMorePages() && pages < maxStatePages; pages++ {
+		page, err := paginator.NextPage(ctx, func(o *s3_sdk.Options) {
+			o.Region = region
+		})
+		if err != nil {
+			return states, err
+		}
+		for _, o := range page.Contents {
+			if strings.HasSuffix(aws_sdk.ToString(o.Key), stateFileSuffix) {
+				states = append(states, aws_sdk.ToString(o.Key))
+			}
+		}
+	}
+
+	return states, nil
+}
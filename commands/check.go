@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/linter"
+)
+
+// check reports security and best practice issues in a generated project
+func check(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	failOn := flags.String("fail-on", linter.SeverityError.String(), i18n.T("commands.check.fail_on"))
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, i18n.T("commands.check.usage"))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	threshold, err := linter.ParseSeverity(*failOn)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	issues, err := linter.Lint(os.DirFS(dir))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintln(stdout, issue)
+	}
+
+	if linter.Exceeds(issues, threshold) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/xsevy/terrapi/i18n"
+)
+
+// command runs with the remaining arguments and returns the process exit code
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"check":   check,
+	"drift":   driftCommand,
+	"env":     env,
+	"lint":    check,
+	"upgrade": upgrade,
+}
+
+// Run executes the command named by the first argument
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		return usage(stderr)
+	}
+
+	c, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, i18n.T("commands.unknown", args[0]))
+		return usage(stderr)
+	}
+
+	return c(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) int {
+	PrintUsage(w)
+	return 2
+}
+
+// PrintUsage prints how terrapi is run and lists the commands
+func PrintUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, i18n.T("commands.usage"))
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+}
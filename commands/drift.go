@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/config"
+	"github.com/xsevy/terrapi/drift"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/project"
+)
+
+// driftCommand reports the differences between a project and the resources deployed in AWS
+func driftCommand(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("drift", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	env := flags.String("env", "", i18n.T("commands.drift.env_flag"))
+	asJSON := flags.Bool("json", false, i18n.T("commands.drift.json_flag"))
+	profile := flags.String("profile", "", i18n.T("commands.drift.profile_flag"))
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, i18n.T("commands.drift.usage"))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	dir, err := project.FindRoot(dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// interrupting the command cancels the requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// the requests are tuned like in the interactive mode, the profile flag overrides the configuration file
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	options := cfg.AWS.Options()
+	if *profile != "" {
+		options = append(options, aws.WithProfile(*profile))
+	}
+
+	awsClient, err := aws.NewAWS(ctx, options...)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	report, err := drift.Detect(ctx, dir, *env, aws.NewAppSync(awsClient), aws.NewLambda(awsClient), aws.NewIAM(awsClient))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	} else {
+		printDriftReport(stdout, report)
+	}
+
+	if report.HasDrift() {
+		return 1
+	}
+	return 0
+}
+
+// printDriftReport prints the differences as a table
+func printDriftReport(w io.Writer, report *drift.Report) {
+	if len(report.Differences) == 0 {
+		fmt.Fprintln(w, i18n.T("commands.drift.none", report.Project, report.Region))
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, i18n.T("commands.drift.header"))
+	for _, d := range report.Differences {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", d.Kind, d.Name, d.Status, d.Detail)
+	}
+	tw.Flush()
+}
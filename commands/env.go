@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/vcs"
+)
+
+// env manages the environments of a project generated with the environments layout
+func env(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("env", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	force := flags.Bool("force", false, i18n.T("commands.force"))
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, i18n.T("commands.env.usage"))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() < 2 || flags.Arg(0) != "add" {
+		flags.Usage()
+		return 2
+	}
+
+	name := flags.Arg(1)
+	dir := "."
+	if flags.NArg() > 2 {
+		dir = flags.Arg(2)
+	}
+
+	dir, err := project.FindRoot(dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	err = vcs.Run(dir, *force, fmt.Sprintf("Add environment %s", name), func() error {
+		return templates.AddEnvironment(dir, name)
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, i18n.T("commands.env.added", name))
+	return 0
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/vcs"
+)
+
+// upgrade merges the output of newer templates into a project generated by an older terrapi
+func upgrade(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("upgrade", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dryRun := flags.Bool("dry-run", false, i18n.T("commands.upgrade.dry_run"))
+	force := flags.Bool("force", false, i18n.T("commands.force"))
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, i18n.T("commands.upgrade.usage"))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	dir, err := project.FindRoot(dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if manifest.Git && !*dryRun && !*force {
+		if clean, err := vcs.Clean(dir); err != nil || !clean {
+			fmt.Fprintln(stderr, errors.Join(vcs.ErrDirtyTree, err))
+			return 2
+		}
+	}
+
+	files, err := templates.Upgrade(dir, *dryRun)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(stdout, i18n.T("commands.upgrade.up_to_date"))
+		return 0
+	}
+
+	conflicts := 0
+	for _, f := range files {
+		fmt.Fprintln(stdout, f)
+		if f.Status == templates.FileConflict {
+			conflicts++
+		}
+	}
+
+	if conflicts > 0 {
+		fmt.Fprintln(stdout, "\n"+i18n.N("commands.upgrade.conflicts", conflicts))
+		return 1
+	}
+
+	// conflicts are left for the user to resolve and commit
+	if manifest.Git && !*dryRun {
+		if err := vcs.Commit(dir, "Upgrade terrapi templates"); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	return 0
+}
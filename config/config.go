@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/helpers"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration of the interactive mode
+type Config struct {
+	Keys  helpers.KeyMap
+	Theme helpers.Theme
+	// NoColor is set by the NO_COLOR environment variable, the theme is replaced by helpers.NoColorTheme
+	NoColor bool
+	AWS     AWS
+}
+
+// AWS selects the account the forms list resources from and tunes the requests, the zero values keep
+// the defaults of the SDK
+type AWS struct {
+	// Profile of the shared configuration, $AWS_PROFILE or the default profile when empty
+	Profile string `yaml:"profile"`
+	Region  string `yaml:"region"`
+	// MaxAttempts counts the first attempt of a request and its retries
+	MaxAttempts int `yaml:"max_attempts"`
+	// Timeout limits every attempt, like 30s
+	Timeout time.Duration `yaml:"timeout"`
+	// EnabledRegionsOnly hides the regions not enabled for the account
+	EnabledRegionsOnly bool `yaml:"enabled_regions_only"`
+	// CacheTTL is how long the lists of the forms are served before being revalidated, cache.DefaultTTL when zero
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// ProductionAccounts are the IDs of the accounts the resources are only created in after a confirmation
+	ProductionAccounts []string `yaml:"production_accounts"`
+}
+
+// Options applies the aws section of the configuration, the zero values are skipped by aws.NewAWS
+func (a AWS) Options() []aws.Option {
+	return []aws.Option{
+		aws.WithProfile(a.Profile),
+		aws.WithRegion(a.Region),
+		aws.WithMaxAttempts(a.MaxAttempts),
+		aws.WithTimeout(a.Timeout),
+		aws.WithEnabledRegionsOnly(a.EnabledRegionsOnly),
+	}
+}
+
+// accountID matches the IDs of the AWS accounts, YAML needs them quoted to keep their leading zeros
+var accountID = regexp.MustCompile(`^[0-9]{12}$`)
+
+// file is the format of the configuration file
+type file struct {
+	// Keymap is the preset the key bindings start from
+	Keymap string `yaml:"keymap"`
+	// Keys remaps bindings by name, see bindings
+	Keys  map[string][]string `yaml:"keys"`
+	Theme string              `yaml:"theme"`
+	// Themes defines themes in addition to the built-in ones
+	Themes map[string]helpers.Theme `yaml:"themes"`
+	AWS    AWS                      `yaml:"aws"`
+}
+
+// DefaultPath returns the path of the configuration file, in $XDG_CONFIG_HOME or ~/.config
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "terrapi", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path, a missing file gives the default configuration
+func Load(path string) (*Config, error) {
+	var f file
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", path, err)
+		}
+	}
+
+	config, err := f.config()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", path, err)
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		config.NoColor = true
+		config.Theme = helpers.Themes[helpers.NoColorTheme]
+	}
+
+	return config, nil
+}
+
+// config applies the file to the default configuration
+func (f file) config() (*Config, error) {
+	keys, err := keyMap(f.Keymap, f.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	name := f.Theme
+	if name == "" {
+		name = helpers.DefaultTheme
+	}
+	theme, ok := f.Themes[name]
+	if !ok {
+		theme, ok = helpers.Themes[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %s, choose one of: %s", name, strings.Join(f.themeNames(), ", "))
+	}
+
+	if f.AWS.MaxAttempts < 0 {
+		return nil, errors.New("aws max_attempts can not be negative")
+	}
+	if f.AWS.Timeout < 0 {
+		return nil, errors.New("aws timeout can not be negative")
+	}
+	if f.AWS.CacheTTL < 0 {
+		return nil, errors.New("aws cache_ttl can not be negative")
+	}
+	for _, account := range f.AWS.ProductionAccounts {
+		if !accountID.MatchString(account) {
+			return nil, fmt.Errorf("aws production account %q is not a 12 digit account ID", account)
+		}
+	}
+
+	return &Config{Keys: keys, Theme: theme, AWS: f.AWS}, nil
+}
+
+// themeNames lists the built-in themes and the themes of the file
+func (f file) themeNames() []string {
+	var names []string
+	for name := range helpers.Themes {
+		names = append(names, name)
+	}
+	for name := range f.Themes {
+		if _, ok := helpers.Themes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
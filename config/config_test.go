@@ -0,0 +1,171 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xsevy/terrapi/helpers"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Theme != helpers.Themes[helpers.DefaultTheme] {
+		t.Errorf("expected the default theme, got %+v", config.Theme)
+	}
+	if !reflect.DeepEqual(config.Keys.Up.Keys(), helpers.DefaultKeyMap().Up.Keys()) {
+		t.Errorf("expected the default keys, got %v", config.Keys.Up.Keys())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	tcs := []struct {
+		name    string
+		content string
+		check   func(t *testing.T, config *Config)
+		err     string
+	}{
+		{
+			name:    "vim preset",
+			content: "keymap: vim\n",
+			check: func(t *testing.T, config *Config) {
+				if !reflect.DeepEqual(config.Keys.PageDown.Keys(), []string{"pgdown", "ctrl+f", "ctrl+d"}) {
+					t.Errorf("unexpected page down keys %v", config.Keys.PageDown.Keys())
+				}
+				if config.Keys.PageDown.Help().Key != "pgdown/ctrl+f/ctrl+d" || config.Keys.PageDown.Help().Desc != "page down " {
+					t.Errorf("unexpected page down help %+v", config.Keys.PageDown.Help())
+				}
+			},
+		},
+		{
+			name:    "emacs preset moves select none away from down",
+			content: "keymap: emacs\n",
+			check: func(t *testing.T, config *Config) {
+				if !reflect.DeepEqual(config.Keys.SelectNone.Keys(), []string{"alt+n"}) {
+					t.Errorf("unexpected select none keys %v", config.Keys.SelectNone.Keys())
+				}
+			},
+		},
+		{
+			name:    "remapped binding",
+			content: "keymap: vim\nkeys:\n  up: [up, w]\n  enter: [enter, \" \"]\n  toggle: [x]\n",
+			check: func(t *testing.T, config *Config) {
+				if !reflect.DeepEqual(config.Keys.Up.Keys(), []string{"up", "w"}) || config.Keys.Up.Help().Key != "↑/w" {
+					t.Errorf("unexpected up binding %v %+v", config.Keys.Up.Keys(), config.Keys.Up.Help())
+				}
+				if config.Keys.Enter.Help().Key != "↵/space" {
+					t.Errorf("unexpected enter help %+v", config.Keys.Enter.Help())
+				}
+				if !reflect.DeepEqual(config.Keys.Down.Keys(), []string{"down", "j", "ctrl+j"}) {
+					t.Errorf("expected the vim down keys, got %v", config.Keys.Down.Keys())
+				}
+			},
+		},
+		{
+			name:    "built-in theme",
+			content: "theme: high-contrast\n",
+			check: func(t *testing.T, config *Config) {
+				if config.Theme != helpers.Themes[helpers.HighContrastTheme] {
+					t.Errorf("expected the high contrast theme, got %+v", config.Theme)
+				}
+			},
+		},
+		{
+			name:    "custom theme",
+			content: "theme: solarized\nthemes:\n  solarized:\n    muted: \"#586e75\"\n    accent: \"#268bd2\"\n    error: \"#dc322f\"\n",
+			check: func(t *testing.T, config *Config) {
+				expected := helpers.Theme{Muted: "#586e75", Accent: "#268bd2", Error: "#dc322f"}
+				if config.Theme != expected {
+					t.Errorf("expected %+v, got %+v", expected, config.Theme)
+				}
+			},
+		},
+		{
+			name:    "aws settings",
+			content: "aws:\n  profile: sso-dev\n  region: eu-west-1\n  max_attempts: 5\n  timeout: 30s\n  enabled_regions_only: true\n  cache_ttl: 1h\n  production_accounts: [\"012345678901\"]\n",
+			check: func(t *testing.T, config *Config) {
+				expected := AWS{
+					Profile:            "sso-dev",
+					Region:             "eu-west-1",
+					MaxAttempts:        5,
+					Timeout:            30 * time.Second,
+					EnabledRegionsOnly: true,
+					CacheTTL:           time.Hour,
+					ProductionAccounts: []string{"012345678901"},
+				}
+				if !reflect.DeepEqual(config.AWS, expected) {
+					t.Errorf("expected %+v, got %+v", expected, config.AWS)
+				}
+			},
+		},
+		{name: "unknown keymap", content: "keymap: nano\n", err: "unknown keymap nano"},
+		{name: "unknown binding", content: "keys:\n  jump: [x]\n", err: "unknown key binding jump"},
+		{name: "empty binding", content: "keys:\n  up: []\n", err: "key binding up has no keys"},
+		{name: "conflicting keys", content: "keys:\n  up: [up, tab]\n", err: `key "tab" is bound to both tab and up`},
+		{name: "unknown theme", content: "theme: dark\n", err: "unknown theme dark, choose one of: default, high-contrast, no-color"},
+		{name: "negative max attempts", content: "aws:\n  max_attempts: -1\n", err: "aws max_attempts can not be negative"},
+		{name: "negative cache ttl", content: "aws:\n  cache_ttl: -1h\n", err: "aws cache_ttl can not be negative"},
+		{name: "invalid production account", content: "aws:\n  production_accounts: [prod]\n", err: `aws production account "prod" is not a 12 digit account ID`},
+		{name: "invalid timeout", content: "aws:\n  timeout: soon\n", err: "unable to read"},
+		{name: "invalid yaml", content: "keys: [\n", err: "unable to read"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := Load(writeConfig(t, tc.content))
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tc.check(t, config)
+		})
+	}
+}
+
+func TestLoadNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	config, err := Load(writeConfig(t, "theme: high-contrast\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.NoColor || config.Theme != helpers.Themes[helpers.NoColorTheme] {
+		t.Errorf("expected NO_COLOR to replace the theme, got %+v", config)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "terrapi", "config.yaml") {
+		t.Errorf("unexpected path %s", path)
+	}
+}
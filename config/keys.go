@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/xsevy/terrapi/helpers"
+)
+
+// names of the keymap presets
+const (
+	DefaultKeymap = "default"
+	VimKeymap     = "vim"
+	EmacsKeymap   = "emacs"
+)
+
+// presets remap bindings of the default keymap. Typing in a list filters it and the letters bound
+// to a move only move until the filter is started, so the presets add control keys next to the letters.
+var presets = map[string]map[string][]string{
+	DefaultKeymap: {},
+	VimKeymap: {
+		"up":        {"up", "k", "ctrl+k"},
+		"down":      {"down", "j", "ctrl+j"},
+		"page_up":   {"pgup", "ctrl+b", "ctrl+u"},
+		"page_down": {"pgdown", "ctrl+f", "ctrl+d"},
+	},
+	EmacsKeymap: {
+		"up":          {"up", "ctrl+p"},
+		"down":        {"down", "ctrl+n"},
+		"page_up":     {"pgup", "alt+v"},
+		"page_down":   {"pgdown", "ctrl+v"},
+		"home":        {"home", "alt+<"},
+		"end":         {"end", "alt+>"},
+		"escape":      {"esc", "ctrl+g"},
+		"select_none": {"alt+n"},
+	},
+}
+
+// keySymbols shorten the keys in the help
+var keySymbols = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"enter": "↵",
+	" ":     "space",
+}
+
+// bindings names the bindings of a keymap in the configuration file
+func bindings(k *helpers.KeyMap) map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":          &k.Up,
+		"down":        &k.Down,
+		"help":        &k.Help,
+		"quit":        &k.Quit,
+		"enter":       &k.Enter,
+		"escape":      &k.Escape,
+		"tab":         &k.Tab,
+		"shift_tab":   &k.ShiftTab,
+		"page_up":     &k.PageUp,
+		"page_down":   &k.PageDown,
+		"home":        &k.Home,
+		"end":         &k.End,
+		"toggle":      &k.Toggle,
+		"select_all":  &k.SelectAll,
+		"select_none": &k.SelectNone,
+		"refresh":     &k.Refresh,
+		"filter":      &k.Filter,
+	}
+}
+
+// keyMap returns the bindings of the preset remapped by keys
+func keyMap(preset string, keys map[string][]string) (helpers.KeyMap, error) {
+	if preset == "" {
+		preset = DefaultKeymap
+	}
+	remapped, ok := presets[preset]
+	if !ok {
+		return helpers.KeyMap{}, fmt.Errorf("unknown keymap %s, choose one of: %s, %s, %s", preset, DefaultKeymap, VimKeymap, EmacsKeymap)
+	}
+
+	k := helpers.DefaultKeyMap()
+	named := bindings(&k)
+
+	for _, remap := range []map[string][]string{remapped, keys} {
+		for name, keys := range remap {
+			binding, ok := named[name]
+			if !ok {
+				return helpers.KeyMap{}, fmt.Errorf("unknown key binding %s, choose one of: %s", name, strings.Join(sortedNames(named), ", "))
+			}
+			if len(keys) == 0 {
+				return helpers.KeyMap{}, fmt.Errorf("key binding %s has no keys", name)
+			}
+			*binding = key.NewBinding(
+				key.WithKeys(keys...),
+				key.WithHelp(helpKeys(keys), binding.Help().Desc),
+			)
+		}
+	}
+
+	return k, checkConflicts(named)
+}
+
+// checkConflicts fails when a key is bound to several bindings, only the first one would ever match
+func checkConflicts(named map[string]*key.Binding) error {
+	bound := map[string]string{}
+	for _, name := range sortedNames(named) {
+		for _, k := range named[name].Keys() {
+			if other, ok := bound[k]; ok {
+				return fmt.Errorf("key %q is bound to both %s and %s", k, other, name)
+			}
+			bound[k] = name
+		}
+	}
+	return nil
+}
+
+func helpKeys(keys []string) string {
+	symbols := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if symbol, ok := keySymbols[k]; ok {
+			k = symbol
+		}
+		symbols = append(symbols, k)
+	}
+	return strings.Join(symbols, "/")
+}
+
+func sortedNames(named map[string]*key.Binding) []string {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,204 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/project"
+)
+
+// Kind is the type of the resource a difference is about
+type Kind string
+
+const (
+	KindAPI            Kind = "api"
+	KindDataSource     Kind = "datasource"
+	KindResolver       Kind = "resolver"
+	KindFunction       Kind = "function"
+	KindLambdaFunction Kind = "lambda"
+	KindIAMRole        Kind = "iam_role"
+	KindSchema         Kind = "schema"
+)
+
+// Status tells on which side a resource is missing
+type Status string
+
+const (
+	// StatusMissing is defined in the project but does not exist in AWS
+	StatusMissing Status = "missing"
+	// StatusUnmanaged exists in AWS but is not defined in the project
+	StatusUnmanaged Status = "unmanaged"
+	// StatusChanged exists on both sides but differs
+	StatusChanged Status = "changed"
+	// StatusUnresolved is defined in the project with a name terrapi can not evaluate
+	StatusUnresolved Status = "unresolved"
+)
+
+// Difference is a single resource which is not the same in the project and in AWS
+type Difference struct {
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (d Difference) String() string {
+	s := fmt.Sprintf("%s %s: %s", d.Kind, d.Name, d.Status)
+	if d.Detail != "" {
+		s += " (" + d.Detail + ")"
+	}
+	return s
+}
+
+// Report is the result of comparing a project with the deployed resources
+type Report struct {
+	Project     string       `json:"project"`
+	Environment string       `json:"environment,omitempty"`
+	Region      string       `json:"region"`
+	APIID       string       `json:"api_id,omitempty"`
+	Differences []Difference `json:"differences"`
+}
+
+// HasDrift reports if the project and AWS are out of sync, unresolved names do not count
+func (r Report) HasDrift() bool {
+	for _, d := range r.Differences {
+		if d.Status != StatusUnresolved {
+			return true
+		}
+	}
+	return false
+}
+
+// Inventory lists the names of the resources of a project, either defined locally or deployed
+type Inventory struct {
+	Region          string
+	API             string
+	DataSources     []string
+	Resolvers       []string
+	Functions       []string
+	LambdaFunctions []string
+	IAMRoles        []string
+	Schema          string
+	// Unresolved lists the addresses of the resources whose name could not be evaluated
+	Unresolved []string
+}
+
+// Detect compares the project in dir with the resources deployed in AWS.
+// env selects the environment of projects using the environments layout.
+func Detect(ctx context.Context, dir string, env string, appsyncClient aws.AppSync, lambdaClient aws.Lambda, iamClient aws.IAM) (*Report, error) {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.HasEnvironments() && env == "" {
+		return nil, fmt.Errorf("project %s uses environments, choose one of: %s", manifest.Name, strings.Join(manifest.Environments, ", "))
+	}
+	if env != "" && !manifest.HasEnvironment(env) {
+		return nil, fmt.Errorf("environment %s does not exist in project %s", env, manifest.Name)
+	}
+
+	local, err := Local(dir, env)
+	if err != nil {
+		return nil, err
+	}
+	if local.Region == "" {
+		local.Region = manifest.Region
+	}
+
+	remote, apiID, err := Remote(ctx, local, manifest.APIID, appsyncClient, lambdaClient, iamClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		Project:     manifest.Name,
+		Environment: env,
+		Region:      local.Region,
+		APIID:       apiID,
+		Differences: Compare(local, remote),
+	}, nil
+}
+
+// Compare lists the differences between the local and the remote inventory
+func Compare(local, remote *Inventory) []Difference {
+	differences := []Difference{}
+
+	if local.API != remote.API {
+		if local.API != "" {
+			differences = append(differences, Difference{Kind: KindAPI, Name: local.API, Status: StatusMissing})
+		}
+		if remote.API != "" {
+			differences = append(differences, Difference{Kind: KindAPI, Name: remote.API, Status: StatusUnmanaged})
+		}
+	}
+
+	differences = append(differences, compareNames(KindDataSource, local.DataSources, remote.DataSources)...)
+	differences = append(differences, compareNames(KindResolver, local.Resolvers, remote.Resolvers)...)
+	differences = append(differences, compareNames(KindFunction, local.Functions, remote.Functions)...)
+	differences = append(differences, compareNames(KindLambdaFunction, local.LambdaFunctions, remote.LambdaFunctions)...)
+	differences = append(differences, compareNames(KindIAMRole, local.IAMRoles, remote.IAMRoles)...)
+
+	if remote.API != "" {
+		differences = append(differences, compareSchemas(local.Schema, remote.Schema)...)
+	}
+
+	for _, address := range local.Unresolved {
+		differences = append(differences, Difference{Kind: kindOfAddress(address), Name: address, Status: StatusUnresolved})
+	}
+
+	return differences
+}
+
+// compareNames returns the names found only on one side, sorted
+func compareNames(kind Kind, local, remote []string) []Difference {
+	differences := []Difference{}
+
+	for _, name := range subtract(local, remote) {
+		differences = append(differences, Difference{Kind: kind, Name: name, Status: StatusMissing})
+	}
+	for _, name := range subtract(remote, local) {
+		differences = append(differences, Difference{Kind: kind, Name: name, Status: StatusUnmanaged})
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Name < differences[j].Name
+	})
+
+	return differences
+}
+
+// subtract returns the names of a which are not in b
+func subtract(a, b []string) []string {
+	inB := map[string]bool{}
+	for _, name := range b {
+		inB[name] = true
+	}
+
+	var names []string
+	for _, name := range a {
+		if !inB[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// kindOfAddress returns the kind of a terraform resource address like module.users.aws_iam_role.lambda_role
+func kindOfAddress(address string) Kind {
+	parts := strings.Split(address, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	typ := parts[len(parts)-2]
+	for kind, resourceType := range resourceTypes {
+		if resourceType == typ {
+			return kind
+		}
+	}
+	return ""
+}
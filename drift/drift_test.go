@@ -0,0 +1,294 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/templates"
+)
+
+type fakeAppSync struct {
+	apis    []aws.GraphqlAPI
+	exports map[string]*aws.APIExport
+}
+
+func (f fakeAppSync) Regions(ctx context.Context) ([]string, error) {
+	return []string{"eu-central-1"}, nil
+}
+
+func (f fakeAppSync) ListAPIs(ctx context.Context, region string) ([]aws.GraphqlAPI, error) {
+	return f.apis, nil
+}
+
+func (f fakeAppSync) ExportAPI(ctx context.Context, region string, apiID string) (*aws.APIExport, error) {
+	export, ok := f.exports[apiID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return export, nil
+}
+
+type fakeLambda struct {
+	functions []string
+}
+
+func (f fakeLambda) ListFunctions(ctx context.Context) ([]string, error) {
+	return f.functions, nil
+}
+
+func (f fakeLambda) ListRuntimes(ctx context.Context) ([]string, error) {
+	return []string{"python3.11"}, nil
+}
+
+func (f fakeLambda) FunctionExists(ctx context.Context, region string, name string) (bool, error) {
+	return contains(f.functions, name), nil
+}
+
+type fakeIAM struct {
+	roles []string
+}
+
+func (f fakeIAM) RoleExists(ctx context.Context, name string) (bool, error) {
+	return contains(f.roles, name), nil
+}
+
+// createProject generates an API using environments with a lambda data source called posts
+func createProject(t *testing.T) string {
+	t.Helper()
+
+	dest := t.TempDir()
+	replacements := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		BackendLockTable:         "lock",
+		BackendKey:               "users/terraform.tfstate",
+		AuthorizerLambdaFunction: "authorizer",
+		Environments:             []string{"dev", "prod"},
+	}
+	if err := templates.CreateResources(replacements.ID, dest, replacements); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+
+	files := map[string]string{
+		"main.tf": `
+module "posts_data_source" {
+  source      = "./posts"
+  name_suffix = "_${var.environment}"
+}`,
+		"datasources.tf": `
+resource "aws_appsync_datasource" "posts_data_source" {
+  name = "${local.project_name}_posts_data_source"
+  api_id = aws_appsync_graphql_api.appsync.id
+  type = "AWS_LAMBDA"
+}`,
+		"resolvers.tf": `
+resource "aws_appsync_resolver" "query_posts" {
+  api_id = aws_appsync_graphql_api.appsync.id
+  type   = "Query"
+  field  = "posts"
+}
+
+resource "aws_appsync_function" "unknown" {
+  name = data.external.name.result
+}`,
+		"posts/variables.tf": `
+variable "name_suffix" {
+  type    = string
+  default = ""
+}`,
+		"posts/locals.tf": `
+locals {
+  project_name = "posts${var.name_suffix}"
+}`,
+		"posts/lambda.tf": `
+resource "aws_lambda_function" "lambda_function" {
+  function_name = local.project_name
+}
+
+resource "aws_iam_role" "lambda_role" {
+  name = "${local.project_name}-lambda-role"
+}`,
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "posts"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for name, content := range files {
+		f, err := os.OpenFile(filepath.Join(dir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := f.WriteString(content); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		f.Close()
+	}
+
+	return dir
+}
+
+func TestLocal(t *testing.T) {
+	dir := createProject(t)
+
+	inventory, err := Local(dir, "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &Inventory{
+		Region:          "eu-central-1",
+		API:             "users_dev_appsync",
+		DataSources:     []string{"users_dev_posts_data_source"},
+		Resolvers:       []string{"Query.posts"},
+		LambdaFunctions: []string{"posts_dev"},
+		IAMRoles:        []string{"users_dev_iam_lambda_role", "users_dev_iam_appsync_role", "posts_dev-lambda-role"},
+		Unresolved:      []string{"aws_appsync_function.unknown"},
+	}
+	inventory.Schema = ""
+	if !reflect.DeepEqual(inventory, expected) {
+		t.Errorf("expected %+v, got %+v", expected, inventory)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	dir := createProject(t)
+	schema, err := os.ReadFile(filepath.Join(dir, "schema.graphql"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	appsyncClient := fakeAppSync{
+		apis: []aws.GraphqlAPI{{ID: "abc", Name: "users_dev_appsync"}},
+		exports: map[string]*aws.APIExport{
+			"abc": {
+				API:    aws.GraphqlAPI{ID: "abc", Name: "users_dev_appsync", LogRoleARN: "arn:aws:iam::123456789012:role/users_dev_iam_appsync_role"},
+				Schema: string(schema) + "\ntype Extra @aws_lambda {\n  id: ID!\n}\n",
+				DataSources: []aws.DataSource{
+					{Name: "users_dev_posts_data_source", LambdaFunctionARN: "arn:aws:lambda:eu-central-1:123456789012:function:posts_dev"},
+					{Name: "manual", LambdaFunctionARN: "arn:aws:lambda:eu-central-1:123456789012:function:manual:live"},
+				},
+			},
+		},
+	}
+	lambdaClient := fakeLambda{functions: []string{"posts_dev"}}
+	iamClient := fakeIAM{roles: []string{"users_dev_iam_appsync_role", "posts_dev-lambda-role"}}
+
+	if _, err := Detect(context.Background(), dir, "", appsyncClient, lambdaClient, iamClient); err == nil {
+		t.Error("expected error without an environment, got nil")
+	}
+	if _, err := Detect(context.Background(), dir, "stage", appsyncClient, lambdaClient, iamClient); err == nil {
+		t.Error("expected error with an unknown environment, got nil")
+	}
+
+	report, err := Detect(context.Background(), dir, "dev", appsyncClient, lambdaClient, iamClient)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"datasource manual: unmanaged",
+		"resolver Query.posts: missing",
+		"lambda manual: unmanaged",
+		"iam_role users_dev_iam_lambda_role: missing",
+		"schema Extra: unmanaged",
+		"schema Extra.id: unmanaged",
+		"function aws_appsync_function.unknown: unresolved",
+	}
+	var differences []string
+	for _, d := range report.Differences {
+		differences = append(differences, d.String())
+	}
+	if !reflect.DeepEqual(differences, expected) {
+		t.Errorf("expected differences\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(differences, "\n"))
+	}
+	if report.APIID != "abc" || report.Region != "eu-central-1" || report.Environment != "dev" {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if !report.HasDrift() {
+		t.Error("expected drift")
+	}
+}
+
+func TestCompareSchemas(t *testing.T) {
+	tcs := []struct {
+		name     string
+		local    string
+		remote   string
+		expected []string
+	}{
+		{
+			name:     "same schema with directives, descriptions and comments",
+			local:    "# comment\ntype Query {\n  \"the user\"\n  user(id: ID!): User\n}\n\ntype User {\n  id: ID!\n}",
+			remote:   "schema {\n  query: Query\n}\n\ntype Query @aws_lambda {\n  user(id: ID!): User @aws_lambda\n}\n\ntype User {\n  id: ID!\n}",
+			expected: nil,
+		},
+		{
+			name:     "changed field type",
+			local:    "type User {\n  id: ID!\n  age: Int\n}",
+			remote:   "type User {\n  id: ID!\n  age: String\n}",
+			expected: []string{"schema User.age: changed (project :Int, deployed :String)"},
+		},
+		{
+			name:     "enum values and unions",
+			local:    "enum Role {\n  ADMIN\n  USER\n}\n\nunion Result = User | Error",
+			remote:   "enum Role {\n  ADMIN\n}\n\nunion Result = User",
+			expected: []string{"schema Result: changed (project union=User|Error, deployed union=User)", "schema Role.USER: missing"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var differences []string
+			for _, d := range compareSchemas(tc.local, tc.remote) {
+				differences = append(differences, d.String())
+			}
+			if !reflect.DeepEqual(differences, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, differences)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	m := &module{
+		locals: map[string]string{
+			"name":   `"${var.prefix}_api"`,
+			"loop":   "local.loop",
+			"region": "var.region",
+		},
+		vars: map[string]string{"prefix": "users"},
+	}
+
+	tcs := []struct {
+		expr     string
+		expected string
+		ok       bool
+	}{
+		{expr: `"plain"`, expected: "plain", ok: true},
+		{expr: "local.name", expected: "users_api", ok: true},
+		{expr: `"${local.name}-role"`, expected: "users_api-role", ok: true},
+		{expr: `"$${escaped}"`, expected: "${escaped}", ok: true},
+		{expr: "local.loop", ok: false},
+		{expr: "local.region", ok: false},
+		{expr: "data.aws_region.current.name", ok: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.expr, func(t *testing.T) {
+			value, ok := m.evaluate(tc.expr, 0)
+			if ok != tc.ok || value != tc.expected {
+				t.Errorf("expected %q %v, got %q %v", tc.expected, tc.ok, value, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package drift
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xsevy/terrapi/helpers/hcl"
+)
+
+// resourceTypes maps the kinds to the terraform resource types defining them
+var resourceTypes = map[Kind]string{
+	KindAPI:            "aws_appsync_graphql_api",
+	KindDataSource:     "aws_appsync_datasource",
+	KindResolver:       "aws_appsync_resolver",
+	KindFunction:       "aws_appsync_function",
+	KindLambdaFunction: "aws_lambda_function",
+	KindIAMRole:        "aws_iam_role",
+}
+
+var (
+	referenceRegex     = regexp.MustCompile(`^(local|var)\.([a-zA-Z0-9_-]+)$`)
+	interpolationRegex = regexp.MustCompile(`\$\{\s*([^}]*?)\s*\}`)
+)
+
+// maxEvaluationDepth stops locals referencing each other in a loop
+const maxEvaluationDepth = 10
+
+// module is a terraform module directory with the values known to it
+type module struct {
+	address string
+	blocks  []hcl.Block
+	locals  map[string]string
+	vars    map[string]string
+}
+
+// Local lists the resources defined by the terraform files of the project in dir.
+// For projects using the environments layout env selects the tfvars file to use.
+func Local(dir string, env string) (*Inventory, error) {
+	vars := map[string]string{}
+	if env != "" {
+		tfvars, err := readTfvars(filepath.Join(dir, "environments", env+".tfvars"))
+		if err != nil {
+			return nil, err
+		}
+		vars = tfvars
+	}
+
+	inventory := &Inventory{}
+	if err := inventory.addModule("", dir, vars); err != nil {
+		return nil, err
+	}
+
+	schema, err := os.ReadFile(filepath.Join(dir, "schema.graphql"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	inventory.Schema = string(schema)
+
+	return inventory, nil
+}
+
+// addModule adds the resources of the module in dir and of the local modules it calls.
+// vars are the values given to the module, they take precedence over the defaults.
+func (i *Inventory) addModule(address string, dir string, vars map[string]string) error {
+	m, err := readModule(address, dir, vars)
+	if err != nil {
+		return err
+	}
+
+	if address == "" {
+		if region, ok := m.evaluate("local.aws_region", 0); ok {
+			i.Region = region
+		}
+	}
+
+	for _, b := range m.blocks {
+		switch {
+		case b.Type == "resource":
+			i.addResource(m, b)
+		case b.Type == "module":
+			source, _, _ := b.Attribute("source")
+			source = strings.Trim(source, `"`)
+			if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
+				continue
+			}
+
+			childVars := map[string]string{}
+			for name, expr := range b.Attributes() {
+				if value, ok := m.evaluate(expr, 0); ok {
+					childVars[name] = value
+				}
+			}
+
+			childAddress := strings.TrimPrefix(address+".module."+b.Label(0), ".")
+			if err := i.addModule(childAddress, filepath.Join(dir, source), childVars); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addResource adds the name of a resource when it is one of the kinds compared
+func (i *Inventory) addResource(m *module, b hcl.Block) {
+	address := strings.TrimPrefix(m.address+"."+b.Label(0)+"."+b.Label(1), ".")
+
+	attribute := func(name string) (string, bool) {
+		expr, _, ok := b.Attribute(name)
+		if !ok {
+			return "", false
+		}
+		return m.evaluate(expr, 0)
+	}
+
+	var ok bool
+	var name string
+	switch b.Label(0) {
+	case resourceTypes[KindAPI]:
+		if name, ok = attribute("name"); ok {
+			i.API = name
+		}
+	case resourceTypes[KindDataSource]:
+		if name, ok = attribute("name"); ok {
+			i.DataSources = append(i.DataSources, name)
+		}
+	case resourceTypes[KindResolver]:
+		typ, typeOK := attribute("type")
+		field, fieldOK := attribute("field")
+		if ok = typeOK && fieldOK; ok {
+			i.Resolvers = append(i.Resolvers, typ+"."+field)
+		}
+	case resourceTypes[KindFunction]:
+		if name, ok = attribute("name"); ok {
+			i.Functions = append(i.Functions, name)
+		}
+	case resourceTypes[KindLambdaFunction]:
+		if name, ok = attribute("function_name"); ok {
+			i.LambdaFunctions = append(i.LambdaFunctions, name)
+		}
+	case resourceTypes[KindIAMRole]:
+		if name, ok = attribute("name"); ok {
+			i.IAMRoles = append(i.IAMRoles, name)
+		}
+	default:
+		return
+	}
+
+	if !ok {
+		i.Unresolved = append(i.Unresolved, address)
+	}
+}
+
+// readModule parses the terraform files of dir and collects its locals and variable defaults
+func readModule(address string, dir string, vars map[string]string) (*module, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	m := &module{
+		address: address,
+		locals:  map[string]string{},
+		vars:    map[string]string{},
+	}
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m.blocks = append(m.blocks, hcl.Parse(strings.Split(string(content), "\n"))...)
+	}
+
+	for _, b := range m.blocks {
+		switch b.Type {
+		case "locals":
+			for name, expr := range b.Attributes() {
+				m.locals[name] = expr
+			}
+		case "variable":
+			if expr, _, ok := b.Attribute("default"); ok {
+				if value, ok := literal(expr); ok {
+					m.vars[b.Label(0)] = value
+				}
+			}
+		}
+	}
+
+	for name, value := range vars {
+		m.vars[name] = value
+	}
+
+	return m, nil
+}
+
+// evaluate returns the value of a string expression which may reference locals and variables
+func (m *module) evaluate(expr string, depth int) (string, bool) {
+	if depth > maxEvaluationDepth {
+		return "", false
+	}
+
+	if match := referenceRegex.FindStringSubmatch(expr); match != nil {
+		if match[1] == "var" {
+			value, ok := m.vars[match[2]]
+			return value, ok
+		}
+
+		local, ok := m.locals[match[2]]
+		if !ok {
+			return "", false
+		}
+		return m.evaluate(local, depth+1)
+	}
+
+	if len(expr) < 2 || !strings.HasPrefix(expr, `"`) || !strings.HasSuffix(expr, `"`) {
+		return "", false
+	}
+
+	resolved := true
+	value := interpolationRegex.ReplaceAllStringFunc(protectEscapes(expr[1:len(expr)-1]), func(interpolation string) string {
+		value, ok := m.evaluate(interpolationRegex.FindStringSubmatch(interpolation)[1], depth+1)
+		if !ok {
+			resolved = false
+		}
+		return value
+	})
+	if !resolved {
+		return "", false
+	}
+
+	return unescape(value), true
+}
+
+// literal returns the value of a quoted string without interpolations
+func literal(expr string) (string, bool) {
+	if len(expr) < 2 || !strings.HasPrefix(expr, `"`) || !strings.HasSuffix(expr, `"`) {
+		return "", false
+	}
+
+	value := protectEscapes(expr[1 : len(expr)-1])
+	if interpolationRegex.MatchString(value) {
+		return "", false
+	}
+	return unescape(value), true
+}
+
+// protectEscapes hides escaped interpolations like $${ from interpolationRegex, unescape restores them
+func protectEscapes(value string) string {
+	return strings.ReplaceAll(value, "$${", "\x00")
+}
+
+// unescape reverts the escaping done by templates.hclString
+func unescape(value string) string {
+	value = strings.NewReplacer("\x00", "${", "%%{", "%{").Replace(value)
+	if unquoted, err := strconv.Unquote(`"` + value + `"`); err == nil {
+		return unquoted
+	}
+	return value
+}
+
+// readTfvars reads the string values of a tfvars file
+func readTfvars(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the variables of the environment: %w", err)
+	}
+
+	vars := map[string]string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		name, expr, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		if value, ok := literal(strings.TrimSpace(expr)); ok {
+			vars[strings.TrimSpace(name)] = value
+		}
+	}
+
+	return vars, nil
+}
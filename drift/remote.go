@@ -0,0 +1,124 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xsevy/terrapi/aws"
+)
+
+// Remote lists the deployed resources matching the local inventory.
+// The API is looked up by apiID, or by the name of the local API when apiID is empty.
+// It returns the inventory and the ID of the API, which is empty when the API is not deployed.
+func Remote(ctx context.Context, local *Inventory, apiID string, appsyncClient aws.AppSync, lambdaClient aws.Lambda, iamClient aws.IAM) (*Inventory, string, error) {
+	remote := &Inventory{Region: local.Region}
+
+	if apiID == "" && local.API != "" {
+		apis, err := appsyncClient.ListAPIs(ctx, local.Region)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, api := range apis {
+			if api.Name == local.API {
+				apiID = api.ID
+				break
+			}
+		}
+	}
+
+	if apiID != "" {
+		export, err := appsyncClient.ExportAPI(ctx, local.Region, apiID)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to read api %s: %w", apiID, err)
+		}
+		remote.addExport(export)
+	}
+
+	for _, name := range local.LambdaFunctions {
+		if contains(remote.LambdaFunctions, name) {
+			continue
+		}
+		exists, err := lambdaClient.FunctionExists(ctx, local.Region, name)
+		if err != nil {
+			return nil, "", err
+		}
+		if exists {
+			remote.LambdaFunctions = append(remote.LambdaFunctions, name)
+		}
+	}
+
+	for _, name := range local.IAMRoles {
+		if contains(remote.IAMRoles, name) {
+			continue
+		}
+		exists, err := iamClient.RoleExists(ctx, name)
+		if err != nil {
+			return nil, "", err
+		}
+		if exists {
+			remote.IAMRoles = append(remote.IAMRoles, name)
+		}
+	}
+
+	return remote, apiID, nil
+}
+
+// addExport adds the resources of the API and the lambda functions and roles it uses
+func (i *Inventory) addExport(export *aws.APIExport) {
+	i.API = export.API.Name
+	i.Schema = export.Schema
+
+	if export.API.LogRoleARN != "" {
+		i.IAMRoles = appendUnique(i.IAMRoles, roleName(export.API.LogRoleARN))
+	}
+
+	for _, ds := range export.DataSources {
+		i.DataSources = append(i.DataSources, ds.Name)
+		if ds.ServiceRoleARN != "" {
+			i.IAMRoles = appendUnique(i.IAMRoles, roleName(ds.ServiceRoleARN))
+		}
+		if ds.LambdaFunctionARN != "" {
+			i.LambdaFunctions = appendUnique(i.LambdaFunctions, functionName(ds.LambdaFunctionARN))
+		}
+	}
+
+	for _, r := range export.Resolvers {
+		i.Resolvers = append(i.Resolvers, r.TypeName+"."+r.FieldName)
+	}
+
+	for _, f := range export.Functions {
+		i.Functions = append(i.Functions, f.Name)
+	}
+}
+
+// roleName returns the name of a role from its ARN, arn:aws:iam::123456789012:role/path/name
+func roleName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// functionName returns the name of a lambda function from its ARN,
+// arn:aws:lambda:eu-west-1:123456789012:function:name with an optional :alias
+func functionName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 {
+		return arn
+	}
+	return parts[6]
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(names []string, name string) []string {
+	if contains(names, name) {
+		return names
+	}
+	return append(names, name)
+}
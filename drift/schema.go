@@ -0,0 +1,90 @@
+package drift
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	blockStringRegex = regexp.MustCompile(`(?s)""".*?"""`)
+	stringRegex      = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	commentRegex     = regexp.MustCompile(`#[^\n]*`)
+	directiveRegex   = regexp.MustCompile(`@\w+(?:\s*\([^)]*\))?`)
+	definitionRegex  = regexp.MustCompile(`\b(type|input|interface|enum)\s+(\w+)[^{]*\{([^}]*)\}`)
+	scalarRegex      = regexp.MustCompile(`\b(scalar|union)\s+(\w+)(?:\s*=\s*([\w\s|]+))?`)
+	fieldRegex       = regexp.MustCompile(`(\w+)\s*(\([^)]*\))?\s*:\s*([\w\[\]!]+)`)
+	whitespaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// SchemaFields returns the signature of every type and field of a GraphQL schema, by Type.field.
+// Descriptions, comments and directives are ignored, AppSync adds its own directives to the deployed schema.
+func SchemaFields(schema string) map[string]string {
+	schema = blockStringRegex.ReplaceAllString(schema, "")
+	schema = stringRegex.ReplaceAllString(schema, "")
+	schema = commentRegex.ReplaceAllString(schema, "")
+	schema = directiveRegex.ReplaceAllString(schema, "")
+
+	fields := map[string]string{}
+
+	for _, match := range definitionRegex.FindAllStringSubmatch(schema, -1) {
+		kind, name, body := match[1], match[2], match[3]
+		fields[name] = kind
+
+		if kind == "enum" {
+			for _, value := range strings.Fields(body) {
+				fields[name+"."+value] = ""
+			}
+			continue
+		}
+
+		for _, field := range fieldRegex.FindAllStringSubmatch(body, -1) {
+			args := whitespaceRegex.ReplaceAllString(field[2], "")
+			fields[name+"."+field[1]] = args + ":" + field[3]
+		}
+	}
+
+	for _, match := range scalarRegex.FindAllStringSubmatch(schema, -1) {
+		signature := match[1]
+		if match[3] != "" {
+			signature += "=" + whitespaceRegex.ReplaceAllString(match[3], "")
+		}
+		fields[match[2]] = signature
+	}
+
+	return fields
+}
+
+// compareSchemas lists the types and fields which are not the same in the local and the deployed schema
+func compareSchemas(local, remote string) []Difference {
+	localFields := SchemaFields(local)
+	remoteFields := SchemaFields(remote)
+
+	differences := []Difference{}
+	for name, signature := range localFields {
+		remoteSignature, ok := remoteFields[name]
+		switch {
+		case !ok:
+			differences = append(differences, Difference{Kind: KindSchema, Name: name, Status: StatusMissing})
+		case signature != remoteSignature:
+			differences = append(differences, Difference{
+				Kind:   KindSchema,
+				Name:   name,
+				Status: StatusChanged,
+				Detail: fmt.Sprintf("project %s, deployed %s", signature, remoteSignature),
+			})
+		}
+	}
+	for name := range remoteFields {
+		if _, ok := localFields[name]; !ok {
+			differences = append(differences, Difference{Kind: KindSchema, Name: name, Status: StatusUnmanaged})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Name < differences[j].Name
+	})
+
+	return differences
+}
@@ -2,19 +2,15 @@ package bubbles
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/xsevy/terrapi/helpers"
-)
-
-var (
-	buttonStyle        = lipgloss.NewStyle().PaddingTop(1).PaddingBottom(1).PaddingRight(4).PaddingLeft(4)
-	bluredButtonStyle  = buttonStyle.Copy().Background(lipgloss.Color(helpers.Colors.Grey))
-	focusedButtonStyle = buttonStyle.Copy().Background(lipgloss.Color(helpers.Colors.Purple))
+	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/styles"
 )
 
 type ButtonModel struct {
-	text    string
-	focused bool
+	text     string
+	focused  bool
+	disabled bool
+	navigation.Validation
 }
 
 func NewButtonModel(text string, focused bool) *ButtonModel {
@@ -29,11 +25,14 @@ func (m *ButtonModel) Init() tea.Cmd {
 }
 
 func (m *ButtonModel) View() string {
+	if m.disabled {
+		return styles.ButtonStyleDisabled.Render(m.text)
+	}
 	if m.focused {
-		return focusedButtonStyle.Render(m.text)
+		return styles.ButtonStyleFocused.Render(m.text)
 	}
 
-	return bluredButtonStyle.Render(m.text)
+	return styles.ButtonStyleBlured.Render(m.text)
 }
 
 func (m *ButtonModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -52,3 +51,20 @@ func (m *ButtonModel) Blur() {
 func (m *ButtonModel) Value() string {
 	return ""
 }
+
+func (m *ButtonModel) Values() []string {
+	return nil
+}
+
+func (m *ButtonModel) Validate() error {
+	return m.Check(m.Value())
+}
+
+// SetDisabled greys out the button, the form decides whether a disabled button can be pressed
+func (m *ButtonModel) SetDisabled(disabled bool) {
+	m.disabled = disabled
+}
+
+func (m *ButtonModel) Disabled() bool {
+	return m.disabled
+}
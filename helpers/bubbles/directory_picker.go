@@ -0,0 +1,122 @@
+package bubbles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/navigation"
+)
+
+// parentDirectory is listed first to go up a level
+const parentDirectory = ".."
+
+// DirectoryPickerModel browses the directories, its value is the directory being browsed.
+// Enter opens the selected directory.
+type DirectoryPickerModel struct {
+	keys  helpers.KeyMap
+	title string
+	dir   string
+	list  *ListModel
+	err   error
+	navigation.Validation
+}
+
+func NewDirectoryPicker(title string, dir string, focused bool) *DirectoryPickerModel {
+	m := &DirectoryPickerModel{
+		keys:  helpers.Keys,
+		title: title,
+		list:  NewListModel("", nil, false, focused),
+	}
+	m.SetDirectory(dir)
+	return m
+}
+
+func (m *DirectoryPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DirectoryPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Enter) {
+		if selected := m.list.Value(); selected != "" {
+			m.SetDirectory(filepath.Join(m.dir, selected))
+		}
+		return m, nil
+	}
+
+	_, cmd := m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *DirectoryPickerModel) View() string {
+	return m.list.View()
+}
+
+func (m *DirectoryPickerModel) Focus() tea.Cmd {
+	return m.list.Focus()
+}
+
+func (m *DirectoryPickerModel) Blur() {
+	m.list.Blur()
+}
+
+// Value returns the absolute path of the directory being browsed
+func (m *DirectoryPickerModel) Value() string {
+	return m.dir
+}
+
+func (m *DirectoryPickerModel) Values() []string {
+	return singleValue(m.Value())
+}
+
+func (m *DirectoryPickerModel) Validate() error {
+	return m.Check(m.Value())
+}
+
+// SetDirectory browses dir, the picker stays in the current directory when dir can not be read
+func (m *DirectoryPickerModel) SetDirectory(dir string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	entries, err := os.ReadDir(abs)
+	if err != nil {
+		m.err = err
+		m.list.SetTitle(m.listTitle())
+		return
+	}
+
+	var dirs []string
+	if filepath.Dir(abs) != abs {
+		dirs = append(dirs, parentDirectory)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+
+	m.dir = abs
+	m.err = nil
+	focused := m.list.focused
+	m.list = NewListModel(m.listTitle(), dirs, false, focused)
+}
+
+// listTitle shows the browsed directory under the title, with the home directory shortened to ~
+func (m *DirectoryPickerModel) listTitle() string {
+	dir := m.dir
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, home) {
+		dir = "~" + strings.TrimPrefix(dir, home)
+	}
+
+	title := m.title + "\n" + dir
+	if m.err != nil {
+		title += "\n" + m.err.Error()
+	}
+	return title
+}
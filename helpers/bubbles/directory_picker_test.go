@@ -0,0 +1,71 @@
+package bubbles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDirectoryPicker(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "docs", ".git"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.tf"), nil, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	tcs := []struct {
+		name     string
+		keys     []tea.KeyMsg
+		expected string
+	}{
+		{name: "initial directory", keys: nil, expected: root},
+		{name: "open a directory", keys: []tea.KeyMsg{down, enter}, expected: filepath.Join(root, "api")},
+		{name: "open the second directory", keys: []tea.KeyMsg{down, down, enter}, expected: filepath.Join(root, "docs")},
+		{name: "go up and back", keys: []tea.KeyMsg{down, enter, enter, down, down, enter}, expected: filepath.Join(root, "docs")},
+		{name: "go up", keys: []tea.KeyMsg{enter}, expected: filepath.Dir(root)},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewDirectoryPicker("Destination:", root, true)
+			for _, k := range tc.keys {
+				m.Update(k)
+			}
+			if m.Value() != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, m.Value())
+			}
+		})
+	}
+}
+
+func TestDirectoryPickerListsDirectoriesOnly(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"b", "a", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "file"), nil, 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := NewDirectoryPicker("Destination:", root, true)
+	expected := []string{parentDirectory, "a", "b"}
+	if len(m.list.items) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, m.list.items)
+	}
+	for i := range expected {
+		if m.list.items[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, m.list.items)
+		}
+	}
+}
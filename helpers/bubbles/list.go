@@ -1,6 +1,7 @@
 package bubbles
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -10,21 +11,35 @@ import (
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/helpers/functions"
 	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/i18n"
 	"github.com/xsevy/terrapi/styles"
 )
 
-var (
-	itemStyle         = lipgloss.NewStyle()
-	selectedItemStyle = itemStyle.Copy().Background(lipgloss.Color(helpers.Colors.Purple))
+const (
+	listPerPage = 5
+	// maxPaginatorDots hides the dots of longer lists, the count tells the position instead
+	maxPaginatorDots = 10
 )
 
+// ListModel lists items to choose from, typing filters the items with fuzzy matching. The letters bound
+// to a move, like j and k, move until the filter is started by another letter or the filter key, then
+// every letter is typed into the filter and only the keys which can not be typed move.
 type ListModel struct {
-	keys      helpers.KeyMap
-	title     string
-	items     []string
+	keys   helpers.KeyMap
+	title  string
+	items  []string
+	filter string
+	// filtering is set once the filter is started, it ends when the list is blurred or backspace
+	// is pressed with an empty filter
+	filtering bool
+	visible   []functions.FuzzyResult
 	paginator paginator.Model
 	selected  navigation.Selected
 	focused   bool
+	sorted    bool
+	// prefix is rendered before every item
+	prefix func(item string) string
+	navigation.Validation
 }
 
 func NewListModel(title string, items []string, sorted bool, focused bool) *ListModel {
@@ -32,14 +47,11 @@ func NewListModel(title string, items []string, sorted bool, focused bool) *List
 		keys:      helpers.Keys,
 		title:     title,
 		selected:  0,
-		paginator: NewPaginator(5, len(items)),
+		paginator: NewPaginator(listPerPage, len(items)),
 		focused:   focused,
+		sorted:    sorted,
 	}
-	if sorted {
-		lm.items = functions.SortSliceCaseInsensitive(items)
-	} else {
-		lm.items = items
-	}
+	lm.SetItems(items)
 
 	return &lm
 }
@@ -49,30 +61,44 @@ func (m *ListModel) Init() tea.Cmd {
 }
 
 func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	itemsLength := len(m.items)
-
-	start, end := m.paginator.GetSliceBounds(itemsLength)
+	last := len(m.visible) - 1
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		typed := msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace
 		switch {
+		case m.filtering && typed:
+			m.setFilter(m.filter + string(msg.Runes))
+		case key.Matches(msg, m.keys.Filter):
+			m.filtering = true
 		case key.Matches(msg, m.keys.Up):
 			m.selected.Prev()
-			if int(m.selected) < start {
-				m.paginator.PrevPage()
-			}
 		case key.Matches(msg, m.keys.Down):
-			m.selected.Next(itemsLength - 1)
-			if int(m.selected) == end {
-				m.paginator.NextPage()
+			m.selected.Next(last)
+		case key.Matches(msg, m.keys.PageUp):
+			m.selected = navigation.Selected(max(int(m.selected)-listPerPage, 0))
+		case key.Matches(msg, m.keys.PageDown):
+			m.selected = navigation.Selected(max(min(int(m.selected)+listPerPage, last), 0))
+		case key.Matches(msg, m.keys.Home):
+			m.selected = 0
+		case key.Matches(msg, m.keys.End):
+			m.selected = navigation.Selected(max(last, 0))
+		case typed:
+			m.filtering = true
+			m.setFilter(m.filter + string(msg.Runes))
+		case msg.Type == tea.KeyBackspace:
+			if m.filter == "" {
+				m.filtering = false
+				break
 			}
+			runes := []rune(m.filter)
+			m.setFilter(string(runes[:len(runes)-1]))
 		}
 	}
 
-	m.paginator, cmd = m.paginator.Update(msg)
+	m.paginator.Page = int(m.selected) / listPerPage
 
-	return m, cmd
+	return m, nil
 }
 
 func (m *ListModel) View() string {
@@ -81,17 +107,34 @@ func (m *ListModel) View() string {
 	title := styles.GetFocusedTitle(m.title, m.focused)
 	b.WriteString(title + "\n\n")
 
-	start, end := m.paginator.GetSliceBounds(len(m.items))
+	if m.filtering {
+		b.WriteString(styles.AccentStyle.Render(i18n.T("list.filter", m.filter)) + "\n\n")
+		if len(m.visible) == 0 {
+			b.WriteString(styles.DisabledChoiceStyle.Render(i18n.T("list.no_matches")) + "\n\n")
+		}
+	}
+
+	start, end := m.paginator.GetSliceBounds(len(m.visible))
 	for i := start; i < end; i++ {
-		item := m.items[i]
+		style := styles.ChoiceStyle
 		if i == int(m.selected) {
-			b.WriteString(selectedItemStyle.Render(item) + "\n\n")
-		} else {
-			b.WriteString(itemStyle.Render(item) + "\n\n")
+			style = styles.SelectedChoiceStyle
 		}
+		item := m.items[m.visible[i].Index]
+		if m.prefix != nil {
+			b.WriteString(m.prefix(item))
+		}
+		b.WriteString(highlight(item, m.visible[i].Matched, style) + "\n\n")
 	}
 
-	b.WriteString(m.paginator.View() + "\n")
+	var footer []string
+	if m.paginator.TotalPages > 1 && m.paginator.TotalPages <= maxPaginatorDots {
+		footer = append(footer, m.paginator.View())
+	}
+	if len(m.items) > 0 {
+		footer = append(footer, styles.DisabledChoiceStyle.Render(m.count()))
+	}
+	b.WriteString(strings.Join(footer, "  ") + "\n")
 
 	return b.String()
 }
@@ -101,10 +144,115 @@ func (m *ListModel) Focus() tea.Cmd {
 	return nil
 }
 
+// Blur resets the filter, the selected item stays selected
 func (m *ListModel) Blur() {
 	m.focused = false
+	m.filtering = false
+
+	if m.filter == "" {
+		return
+	}
+	value := m.Value()
+	m.setFilter("")
+	m.Select(value)
+}
+
+// Select selects the visible item equal to value, it reports whether there is one
+func (m *ListModel) Select(value string) bool {
+	for i, result := range m.visible {
+		if m.items[result.Index] == value {
+			m.selected = navigation.Selected(i)
+			m.paginator.Page = int(m.selected) / listPerPage
+			return true
+		}
+	}
+	return false
 }
 
 func (m *ListModel) Value() string {
-	return m.items[m.selected]
+	if len(m.visible) == 0 {
+		return ""
+	}
+	return m.items[m.visible[m.selected].Index]
+}
+
+func (m *ListModel) Values() []string {
+	return singleValue(m.Value())
+}
+
+func (m *ListModel) SetTitle(title string) {
+	m.title = title
+}
+
+// SetItems replaces the items of the list and selects the first one, the filter is kept
+func (m *ListModel) SetItems(items []string) {
+	if m.sorted {
+		items = functions.SortSliceCaseInsensitive(items)
+	}
+	m.items = items
+	m.setFilter(m.filter)
+}
+
+func (m *ListModel) Validate() error {
+	return m.Check(m.Value())
+}
+
+// setFilter shows the items matching filter, the best match is selected
+func (m *ListModel) setFilter(filter string) {
+	m.filter = filter
+	m.visible = functions.FuzzyFilter(filter, m.items)
+	m.selected = 0
+	// SetTotalPages ignores an empty list
+	m.paginator.TotalPages = 1
+	m.paginator.SetTotalPages(len(m.visible))
+	m.paginator.Page = 0
+}
+
+// count tells the position of the selected item, and how many items the filter hides
+func (m *ListModel) count() string {
+	position := 0
+	if len(m.visible) > 0 {
+		position = int(m.selected) + 1
+	}
+
+	if m.filter != "" {
+		return i18n.T("list.filtered_count", position, len(m.visible), len(m.items))
+	}
+	return fmt.Sprintf("%d/%d", position, len(m.visible))
+}
+
+// highlight renders item with style, the matched runes are emphasized
+func highlight(item string, matched []int, style lipgloss.Style) string {
+	if len(matched) == 0 {
+		return style.Render(item)
+	}
+
+	matchedStyle := style.Copy().Bold(true).Underline(true)
+	isMatched := make(map[int]bool, len(matched))
+	for _, i := range matched {
+		isMatched[i] = true
+	}
+
+	// runs of matched and unmatched runes are rendered separately, nesting styles would reset the background
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	for i, r := range []rune(item) {
+		if i > 0 && isMatched[i] != runMatched {
+			b.WriteString(renderRun(string(run), runMatched, style, matchedStyle))
+			run = run[:0]
+		}
+		run = append(run, r)
+		runMatched = isMatched[i]
+	}
+	b.WriteString(renderRun(string(run), runMatched, style, matchedStyle))
+
+	return b.String()
+}
+
+func renderRun(run string, matched bool, style, matchedStyle lipgloss.Style) string {
+	if matched {
+		return matchedStyle.Render(run)
+	}
+	return style.Render(run)
 }
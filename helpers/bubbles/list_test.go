@@ -1,6 +1,14 @@
 package bubbles
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/i18n"
+)
 
 func TestListFocus(t *testing.T) {
 	tcs := []struct {
@@ -106,3 +114,162 @@ func TestListValue(t *testing.T) {
 		t.Errorf("expected %s got %s", i[0], l.Value())
 	}
 }
+
+func TestEmptyListValue(t *testing.T) {
+	l := NewListModel("text", []string{}, false, false)
+
+	if l.Value() != "" {
+		t.Errorf("expected empty value got %s", l.Value())
+	}
+}
+
+func TestListSetItems(t *testing.T) {
+	l := NewListModel("title", []string{"b", "a"}, true, true)
+	l.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if l.Value() != "b" {
+		t.Fatalf("expected b, got %s", l.Value())
+	}
+
+	l.SetItems([]string{"d", "c"})
+	if l.Value() != "c" {
+		t.Errorf("expected the first sorted item c, got %s", l.Value())
+	}
+
+	if !l.Select("d") || l.Value() != "d" {
+		t.Errorf("expected d to be selected, got %s", l.Value())
+	}
+	if l.Select("e") || l.Value() != "d" {
+		t.Errorf("expected a missing item to keep the selection, got %s", l.Value())
+	}
+
+	l.SetValidators(navigation.Required())
+	l.SetItems(nil)
+	if err := l.Validate(); err == nil {
+		t.Error("expected an error for an empty required list, got nil")
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	l := NewListModel("Region:", []string{"eu-central-1", "eu-west-1", "us-east-1", "us-west-2"}, false, true)
+
+	for _, r := range "ws" {
+		l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if l.Value() != "eu-west-1" {
+		t.Errorf("expected eu-west-1, got %s", l.Value())
+	}
+	if l.count() != "1/2 of 4" {
+		t.Errorf("expected count 1/2 of 4, got %s", l.count())
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if l.Value() != "us-west-2" {
+		t.Errorf("expected us-west-2, got %s", l.Value())
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	if l.filter != "w" {
+		t.Errorf("expected filter w, got %s", l.filter)
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	l.Update(tea.KeyMsg{Type: tea.KeyDown})
+	l.Blur()
+	if l.filter != "" || len(l.visible) != 4 {
+		t.Errorf("expected the filter reset on blur, got %q with %d items", l.filter, len(l.visible))
+	}
+	if l.Value() != "us-west-2" {
+		t.Errorf("expected the selected item kept on blur, got %s", l.Value())
+	}
+
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("xyz")})
+	if l.Value() != "" || l.count() != "0/0 of 4" {
+		t.Errorf("expected no match, got %q %s", l.Value(), l.count())
+	}
+}
+
+func TestListBoundRunes(t *testing.T) {
+	l := NewListModel("Region:", []string{"eu-central-1", "eu-west-1", "us-east-1"}, false, true)
+
+	// j and k are bound to down and up, they move until the filter is started
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if l.filter != "" || l.Value() != "eu-west-1" {
+		t.Errorf("expected eu-west-1 without filter, got %s with filter %q", l.Value(), l.filter)
+	}
+
+	// a started filter takes every letter, the keys which can not be typed still move
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if l.filter != "uk" {
+		t.Errorf("expected filter uk, got %q", l.filter)
+	}
+	l.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	first := l.Value()
+	l.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if l.filter != "u" || l.Value() == first {
+		t.Errorf("expected down to move from %s with filter u, got %s with filter %q", first, l.Value(), l.filter)
+	}
+}
+
+func TestListFilterKey(t *testing.T) {
+	l := NewListModel("Function:", []string{"authorizer", "jobs_handler", "kinesis_consumer"}, false, true)
+
+	// the filter key starts the filter, so names starting with a bound letter can be filtered
+	for _, r := range "/jobs" {
+		l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if l.filter != "jobs" || l.Value() != "jobs_handler" {
+		t.Errorf("expected jobs_handler with filter jobs, got %s with filter %q", l.Value(), l.filter)
+	}
+
+	// backspace clears the filter and then ends it, j moves again
+	for i := 0; i < 5; i++ {
+		l.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if l.filter != "" || l.Value() != "jobs_handler" {
+		t.Errorf("expected j to move without filter, got %s with filter %q", l.Value(), l.filter)
+	}
+
+	// an empty started filter is shown until the list is blurred
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !strings.Contains(l.View(), i18n.T("list.filter", "")) {
+		t.Errorf("expected the empty filter in\n%s", l.View())
+	}
+	l.Blur()
+	l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	if l.filter != "" || l.Value() != "authorizer" {
+		t.Errorf("expected k to move after blur, got %s with filter %q", l.Value(), l.filter)
+	}
+}
+
+func TestListPages(t *testing.T) {
+	items := []string{}
+	for i := 0; i < 12; i++ {
+		items = append(items, fmt.Sprintf("item%02d", i))
+	}
+	l := NewListModel("Items:", items, false, true)
+
+	tcs := []struct {
+		key      tea.KeyType
+		expected string
+		page     int
+	}{
+		{key: tea.KeyPgDown, expected: "item05", page: 1},
+		{key: tea.KeyPgDown, expected: "item10", page: 2},
+		{key: tea.KeyPgDown, expected: "item11", page: 2},
+		{key: tea.KeyPgUp, expected: "item06", page: 1},
+		{key: tea.KeyHome, expected: "item00", page: 0},
+		{key: tea.KeyEnd, expected: "item11", page: 2},
+		{key: tea.KeyUp, expected: "item10", page: 2},
+	}
+
+	for _, tc := range tcs {
+		l.Update(tea.KeyMsg{Type: tc.key})
+		if l.Value() != tc.expected || l.paginator.Page != tc.page {
+			t.Errorf("after %s expected %s on page %d, got %s on page %d", tc.key, tc.expected, tc.page, l.Value(), l.paginator.Page)
+		}
+	}
+}
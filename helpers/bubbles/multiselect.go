@@ -0,0 +1,119 @@
+package bubbles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/navigation"
+)
+
+const (
+	checkedPrefix   = "[x] "
+	uncheckedPrefix = "[ ] "
+	// valuesSeparator joins the values of a multi-select list into a single value
+	valuesSeparator = ","
+)
+
+// MultiSelectModel is a list choosing any number of items, space toggles the selected item.
+// Typing filters the items like in ListModel, select all chooses the items matching the filter.
+type MultiSelectModel struct {
+	keys   helpers.KeyMap
+	list   *ListModel
+	chosen map[string]bool
+	navigation.Validation
+}
+
+func NewMultiSelectModel(title string, items []string, sorted bool, focused bool) *MultiSelectModel {
+	m := &MultiSelectModel{
+		keys:   helpers.Keys,
+		list:   NewListModel(title, items, sorted, focused),
+		chosen: map[string]bool{},
+	}
+	m.list.prefix = m.checkbox
+	return m
+}
+
+func (m *MultiSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MultiSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.Toggle):
+			if item := m.list.Value(); item != "" {
+				m.chosen[item] = !m.chosen[item]
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.SelectAll):
+			for _, result := range m.list.visible {
+				m.chosen[m.list.items[result.Index]] = true
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.SelectNone):
+			m.chosen = map[string]bool{}
+			return m, nil
+		}
+	}
+
+	_, cmd := m.list.Update(msg)
+	return m, cmd
+}
+
+func (m *MultiSelectModel) View() string {
+	return m.list.View()
+}
+
+func (m *MultiSelectModel) Focus() tea.Cmd {
+	return m.list.Focus()
+}
+
+func (m *MultiSelectModel) Blur() {
+	m.list.Blur()
+}
+
+// Value returns the chosen items separated by commas, so the validators of single value fields apply
+func (m *MultiSelectModel) Value() string {
+	return strings.Join(m.Values(), valuesSeparator)
+}
+
+// Values returns the chosen items in the order of the list
+func (m *MultiSelectModel) Values() []string {
+	var values []string
+	for _, item := range m.list.items {
+		if m.chosen[item] {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// SetValues chooses the given items, the ones not in the list are ignored
+func (m *MultiSelectModel) SetValues(values []string) {
+	m.chosen = map[string]bool{}
+	for _, value := range values {
+		m.chosen[value] = true
+	}
+}
+
+func (m *MultiSelectModel) SetTitle(title string) {
+	m.list.SetTitle(title)
+}
+
+// SetItems replaces the items of the list, chosen items still in the list stay chosen
+func (m *MultiSelectModel) SetItems(items []string) {
+	m.list.SetItems(items)
+}
+
+func (m *MultiSelectModel) Validate() error {
+	return m.Check(m.Value())
+}
+
+func (m *MultiSelectModel) checkbox(item string) string {
+	if m.chosen[item] {
+		return checkedPrefix
+	}
+	return uncheckedPrefix
+}
@@ -0,0 +1,90 @@
+package bubbles
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers/navigation"
+)
+
+func TestMultiSelectValues(t *testing.T) {
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	tcs := []struct {
+		name     string
+		keys     []tea.KeyMsg
+		expected []string
+	}{
+		{
+			name:     "nothing chosen",
+			expected: nil,
+		},
+		{
+			name:     "toggle",
+			keys:     []tea.KeyMsg{space, down, down, space},
+			expected: []string{"layer_a", "layer_c"},
+		},
+		{
+			name:     "toggle twice",
+			keys:     []tea.KeyMsg{space, space},
+			expected: nil,
+		},
+		{
+			name:     "select all",
+			keys:     []tea.KeyMsg{{Type: tea.KeyCtrlA}},
+			expected: []string{"layer_a", "layer_b", "layer_c", "other"},
+		},
+		{
+			name:     "select all matching the filter",
+			keys:     []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("lay")}, {Type: tea.KeyCtrlA}},
+			expected: []string{"layer_a", "layer_b", "layer_c"},
+		},
+		{
+			name:     "select none",
+			keys:     []tea.KeyMsg{{Type: tea.KeyCtrlA}, {Type: tea.KeyCtrlN}},
+			expected: nil,
+		},
+		{
+			name:     "toggle a filtered item",
+			keys:     []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("oth")}, space},
+			expected: []string{"other"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewMultiSelectModel("Layers:", []string{"other", "layer_c", "layer_a", "layer_b"}, true, true)
+			for _, k := range tc.keys {
+				m.Update(k)
+			}
+
+			if !reflect.DeepEqual(m.Values(), tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, m.Values())
+			}
+		})
+	}
+}
+
+func TestMultiSelectValue(t *testing.T) {
+	m := NewMultiSelectModel("Layers:", []string{"a", "b", "c"}, false, true)
+	m.SetValidators(navigation.Required())
+
+	if err := m.Validate(); err == nil {
+		t.Error("expected an error without a chosen item, got nil")
+	}
+
+	m.SetValues([]string{"c", "a", "unknown"})
+	if m.Value() != "a,c" {
+		t.Errorf("expected a,c, got %s", m.Value())
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	m.SetItems([]string{"c", "d"})
+	if !reflect.DeepEqual(m.Values(), []string{"c"}) {
+		t.Errorf("expected [c], got %v", m.Values())
+	}
+}
@@ -2,16 +2,20 @@ package bubbles
 
 import (
 	"github.com/charmbracelet/bubbles/paginator"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/styles"
 )
 
 func NewPaginator(perpage int, itemsLength int) paginator.Model {
 	p := paginator.New()
 	p.Type = paginator.Dots
 	p.PerPage = perpage
-	p.ActiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color(helpers.Colors.Purple)).Render("•")
-	p.InactiveDot = lipgloss.NewStyle().Foreground(lipgloss.Color(helpers.Colors.Grey)).Render("•")
+	p.ActiveDot = styles.AccentStyle.Render("•")
+	p.InactiveDot = styles.DisabledChoiceStyle.Render("•")
+	if helpers.Colors.Accent == "" {
+		// the dots can not be told apart by their color
+		p.InactiveDot = "◦"
+	}
 	p.SetTotalPages(itemsLength)
 
 	return p
@@ -4,12 +4,14 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/xsevy/terrapi/helpers/navigation"
 	"github.com/xsevy/terrapi/styles"
 )
 
 type TextInputModel struct {
 	t     textinput.Model
 	title string
+	navigation.Validation
 }
 
 func NewTextInput(title string, placeholder string, charlimit int) *TextInputModel {
@@ -35,6 +37,10 @@ func (m *TextInputModel) Value() string {
 	return m.t.Value()
 }
 
+func (m *TextInputModel) Values() []string {
+	return singleValue(m.Value())
+}
+
 func (m *TextInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	m.t, cmd = m.t.Update(msg)
@@ -53,3 +59,15 @@ func (m *TextInputModel) Focus() tea.Cmd {
 func (m *TextInputModel) Blur() {
 	m.t.Blur()
 }
+
+func (m *TextInputModel) Validate() error {
+	return m.Check(m.Value())
+}
+
+// singleValue returns the value of a single value field as its values
+func singleValue(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
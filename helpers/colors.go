@@ -1,11 +1,35 @@
 package helpers
 
-type colors struct {
-	Grey   string
-	Purple string
+// Theme holds the colors of the interface, an empty color leaves the terminal default
+type Theme struct {
+	Muted  string `yaml:"muted"`
+	Accent string `yaml:"accent"`
+	// AccentText is the color of the text on an accent background
+	AccentText string `yaml:"accent_text"`
+	Error      string `yaml:"error"`
 }
 
-var Colors = colors{
-	Grey:   "#808080",
-	Purple: "#CC00CC",
+// names of the built-in themes
+const (
+	DefaultTheme      = "default"
+	HighContrastTheme = "high-contrast"
+	NoColorTheme      = "no-color"
+)
+
+var Themes = map[string]Theme{
+	DefaultTheme: {
+		Muted:  "#808080",
+		Accent: "#CC00CC",
+		Error:  "#FF5F5F",
+	},
+	HighContrastTheme: {
+		Muted:      "#D0D0D0",
+		Accent:     "#FFFF00",
+		AccentText: "#000000",
+		Error:      "#FF3030",
+	},
+	NoColorTheme: {},
 }
+
+// Colors is the current theme, styles.SetTheme changes it
+var Colors = Themes[DefaultTheme]
@@ -0,0 +1,77 @@
+package functions
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// bonuses of the fuzzy match score
+const (
+	fuzzyMatchBonus       = 1
+	fuzzyConsecutiveBonus = 5
+	fuzzyWordStartBonus   = 3
+)
+
+// FuzzyMatch reports whether the runes of pattern appear in s in the same order, ignoring case.
+// It returns the indexes of the matched runes of s and a score, higher for consecutive matches,
+// matches at the start of words and matches close to the start of s.
+func FuzzyMatch(pattern string, s string) ([]int, int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	runes := []rune(s)
+	if len(p) == 0 {
+		return nil, 0, true
+	}
+
+	matched := make([]int, 0, len(p))
+	score := 0
+	for i := 0; i < len(runes) && len(matched) < len(p); i++ {
+		if unicode.ToLower(runes[i]) != p[len(matched)] {
+			continue
+		}
+
+		score += fuzzyMatchBonus
+		if len(matched) > 0 && matched[len(matched)-1] == i-1 {
+			score += fuzzyConsecutiveBonus
+		}
+		if i == 0 || isWordStart(runes[i-1], runes[i]) {
+			score += fuzzyWordStartBonus
+		}
+		matched = append(matched, i)
+	}
+
+	if len(matched) < len(p) {
+		return nil, 0, false
+	}
+	return matched, score - matched[0], true
+}
+
+// isWordStart reports whether current starts a word, after a separator or as an upper case letter in camel case
+func isWordStart(previous, current rune) bool {
+	return !unicode.IsLetter(previous) && !unicode.IsDigit(previous) ||
+		unicode.IsLower(previous) && unicode.IsUpper(current)
+}
+
+// FuzzyResult is an item of FuzzyFilter, Index is its position in the filtered items
+type FuzzyResult struct {
+	Index   int
+	Matched []int
+	score   int
+}
+
+// FuzzyFilter returns the items matching pattern, best matches first.
+// Items with the same score keep their order.
+func FuzzyFilter(pattern string, items []string) []FuzzyResult {
+	results := []FuzzyResult{}
+	for i, item := range items {
+		if matched, score, ok := FuzzyMatch(pattern, item); ok {
+			results = append(results, FuzzyResult{Index: i, Matched: matched, score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	return results
+}
@@ -0,0 +1,45 @@
+package functions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tcs := []struct {
+		pattern string
+		s       string
+		matched []int
+		ok      bool
+	}{
+		{pattern: "", s: "users", matched: nil, ok: true},
+		{pattern: "usr", s: "users", matched: []int{0, 1, 3}, ok: true},
+		{pattern: "USR", s: "users", matched: []int{0, 1, 3}, ok: true},
+		{pattern: "euc", s: "eu-central-1", matched: []int{0, 1, 3}, ok: true},
+		{pattern: "sru", s: "users", ok: false},
+		{pattern: "userss", s: "users", ok: false},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.pattern+" "+tc.s, func(t *testing.T) {
+			matched, _, ok := FuzzyMatch(tc.pattern, tc.s)
+			if ok != tc.ok || !reflect.DeepEqual(matched, tc.matched) {
+				t.Errorf("expected %v %t, got %v %t", tc.matched, tc.ok, matched, ok)
+			}
+		})
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	items := []string{"post_authorizer", "users", "authorizer", "AuthorizerFunction", "orders"}
+
+	var filtered []string
+	for _, result := range FuzzyFilter("auth", items) {
+		filtered = append(filtered, items[result.Index])
+	}
+
+	expected := []string{"authorizer", "AuthorizerFunction", "post_authorizer"}
+	if !reflect.DeepEqual(filtered, expected) {
+		t.Errorf("expected %v, got %v", expected, filtered)
+	}
+}
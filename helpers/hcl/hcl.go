@@ -0,0 +1,141 @@
+package hcl
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	blockHeaderRegex  = regexp.MustCompile(`^\s*([a-z_]+)((?:\s+"[^"]*")*)\s*\{\s*$`)
+	blockLabelRegex   = regexp.MustCompile(`"([^"]*)"`)
+	objectHeaderRegex = regexp.MustCompile(`^\s*([a-zA-Z0-9_-]+)\s*=\s*\{\s*$`)
+	attributeRegex    = regexp.MustCompile(`^\s*([a-zA-Z0-9_-]+)\s*=\s*(.*)$`)
+)
+
+// Block is a HCL block like resource "type" "name" { ... }.
+// This is not a full HCL parser, it relies on terraform fmt style formatting
+// which is what terrapi generates.
+type Block struct {
+	Type   string
+	Labels []string
+	// Line is the index of the header line in the file
+	Line int
+	Body []string
+}
+
+// Parse returns the top level blocks of a file
+func Parse(lines []string) []Block {
+	return parseBlocks(lines, 0, blockHeaderRegex)
+}
+
+// Label returns the label at index i, or an empty string
+func (b Block) Label(i int) string {
+	if i < len(b.Labels) {
+		return b.Labels[i]
+	}
+	return ""
+}
+
+// Children returns the blocks nested directly in the block
+func (b Block) Children() []Block {
+	return parseBlocks(b.Body, b.Line+1, blockHeaderRegex)
+}
+
+// Objects returns the object attributes like aws = { ... } nested directly in the block
+func (b Block) Objects() []Block {
+	return parseBlocks(b.Body, b.Line+1, objectHeaderRegex)
+}
+
+// Attribute returns the value and line of an attribute set directly in the block
+func (b Block) Attribute(name string) (string, int, bool) {
+	depth := 0
+
+	for i, line := range b.Body {
+		if depth == 0 {
+			if match := attributeRegex.FindStringSubmatch(line); match != nil && match[1] == name {
+				return strings.TrimSpace(match[2]), b.Line + 1 + i, true
+			}
+		}
+		depth += BraceDepth(line)
+	}
+
+	return "", 0, false
+}
+
+// Attributes returns the single line attributes set directly in the block
+func (b Block) Attributes() map[string]string {
+	attributes := map[string]string{}
+	depth := 0
+
+	for _, line := range b.Body {
+		if depth == 0 {
+			if match := attributeRegex.FindStringSubmatch(line); match != nil {
+				attributes[match[1]] = strings.TrimSpace(match[2])
+			}
+		}
+		depth += BraceDepth(line)
+	}
+
+	return attributes
+}
+
+// parseBlocks returns the top level blocks found in lines, offset is the index of the first line
+func parseBlocks(lines []string, offset int, header *regexp.Regexp) []Block {
+	var blocks []Block
+
+	for i := 0; i < len(lines); i++ {
+		match := header.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+
+		b := Block{Type: match[1], Line: offset + i}
+		if len(match) > 2 {
+			for _, label := range blockLabelRegex.FindAllStringSubmatch(match[2], -1) {
+				b.Labels = append(b.Labels, label[1])
+			}
+		}
+
+		depth := BraceDepth(lines[i])
+		start := i + 1
+		for depth > 0 && i+1 < len(lines) {
+			i++
+			depth += BraceDepth(lines[i])
+		}
+		b.Body = lines[start:i]
+
+		blocks = append(blocks, b)
+	}
+
+	return blocks
+}
+
+// BraceDepth returns how the line changes the nesting level, braces inside strings are skipped
+func BraceDepth(line string) int {
+	depth := 0
+	inString := false
+	interpolation := 0
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && inString:
+			i++
+		case c == '"' && interpolation == 0:
+			inString = !inString
+		case inString && c == '$' && i+1 < len(line) && line[i+1] == '{':
+			interpolation++
+			i++
+		case inString && c == '}' && interpolation > 0:
+			interpolation--
+		case !inString && c == '#':
+			return depth
+		case !inString && c == '{':
+			depth++
+		case !inString && c == '}':
+			depth--
+		}
+	}
+
+	return depth
+}
@@ -1,51 +1,105 @@
 package helpers
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/xsevy/terrapi/i18n"
+)
 
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Help     key.Binding
-	Quit     key.Binding
-	Enter    key.Binding
-	Escape   key.Binding
-	Tab      key.Binding
-	ShiftTab key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+	Enter      key.Binding
+	Escape     key.Binding
+	Tab        key.Binding
+	ShiftTab   key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Home       key.Binding
+	End        key.Binding
+	Toggle     key.Binding
+	SelectAll  key.Binding
+	SelectNone key.Binding
+	Refresh    key.Binding
+	Filter     key.Binding
 }
 
-var Keys = KeyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up "),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down "),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "help "),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("ctrl+c"),
-		key.WithHelp("ctrl+c", "quit "),
-	),
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("↵", "confirm "),
-	),
-	Escape: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "back "),
-	),
-	Tab: key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "next "),
-	),
-	ShiftTab: key.NewBinding(
-		key.WithKeys("shift+tab"),
-		key.WithHelp("shift+tab", "prev "),
-	),
+// Keys are the key bindings of the interactive mode, the configuration can remap them
+var Keys = DefaultKeyMap()
+
+// DefaultKeyMap returns the key bindings used without configuration, their help is in the current language
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", i18n.T("keys.up")+" "),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", i18n.T("keys.down")+" "),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", i18n.T("keys.help")+" "),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", i18n.T("keys.quit")+" "),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("↵", i18n.T("keys.confirm")+" "),
+		),
+		Escape: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", i18n.T("keys.back")+" "),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", i18n.T("keys.next")+" "),
+		),
+		ShiftTab: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", i18n.T("keys.prev")+" "),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup"),
+			key.WithHelp("pgup", i18n.T("keys.page_up")+" "),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown"),
+			key.WithHelp("pgdown", i18n.T("keys.page_down")+" "),
+		),
+		Home: key.NewBinding(
+			key.WithKeys("home"),
+			key.WithHelp("home", i18n.T("keys.first")+" "),
+		),
+		End: key.NewBinding(
+			key.WithKeys("end"),
+			key.WithHelp("end", i18n.T("keys.last")+" "),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", i18n.T("keys.toggle")+" "),
+		),
+		SelectAll: key.NewBinding(
+			key.WithKeys("ctrl+a"),
+			key.WithHelp("ctrl+a", i18n.T("keys.select_all")+" "),
+		),
+		SelectNone: key.NewBinding(
+			key.WithKeys("ctrl+n"),
+			key.WithHelp("ctrl+n", i18n.T("keys.select_none")+" "),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", i18n.T("keys.refresh")+" "),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", i18n.T("keys.filter")+" "),
+		),
+	}
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -55,8 +109,12 @@ func (k KeyMap) ShortHelp() []key.Binding {
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down},
+		{k.PageUp, k.PageDown},
+		{k.Home, k.End},
+		{k.Toggle, k.SelectAll, k.SelectNone},
 		{k.Help, k.Quit},
 		{k.Enter, k.Escape},
 		{k.Tab, k.ShiftTab},
+		{k.Refresh, k.Filter},
 	}
 }
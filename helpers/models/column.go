@@ -1,7 +1,17 @@
 package models
 
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
 type ColumnModel struct {
 	focused bool
+	width   int
+	height  int
+	// offset is the first line shown when the content is taller than the column
+	offset int
 }
 
 func (c ColumnModel) GetFocused() bool {
@@ -11,3 +21,51 @@ func (c ColumnModel) GetFocused() bool {
 func (c *ColumnModel) SetFocused(f bool) {
 	c.focused = f
 }
+
+// SetSize sets the size of the content of the column, inside its border
+func (c *ColumnModel) SetSize(width, height int) {
+	c.width = width
+	c.height = height
+}
+
+func (c ColumnModel) GetWidth() int {
+	return c.width
+}
+
+func (c ColumnModel) GetHeight() int {
+	return c.height
+}
+
+// Wrap wraps content to the width available inside style, so its height can be measured before Render
+func (c ColumnModel) Wrap(style lipgloss.Style, content string) string {
+	if c.width == 0 {
+		return content
+	}
+	return lipgloss.NewStyle().Width(max(c.width-style.GetHorizontalPadding(), 1)).Render(content)
+}
+
+// Render renders content with style at the size of the column. Content taller than the column
+// is scrolled, so the lines from first to last stay visible when they fit.
+func (c *ColumnModel) Render(style lipgloss.Style, content string, first, last int) string {
+	if c.width == 0 || c.height == 0 {
+		return style.Render(content)
+	}
+
+	lines := strings.Split(c.Wrap(style, content), "\n")
+	height := max(c.height-style.GetVerticalPadding(), 1)
+
+	if len(lines) <= height {
+		c.offset = 0
+	} else {
+		if last >= c.offset+height {
+			c.offset = last - height + 1
+		}
+		if first < c.offset {
+			c.offset = first
+		}
+		c.offset = max(min(c.offset, len(lines)-height), 0)
+		lines = lines[c.offset : c.offset+height]
+	}
+
+	return style.Copy().Width(c.width).Height(c.height).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestColumnRenderScrolls(t *testing.T) {
+	var lines []string
+	for i := 0; i < 10; i++ {
+		lines = append(lines, string(rune('a'+i)))
+	}
+	content := strings.Join(lines, "\n")
+	style := lipgloss.NewStyle()
+
+	var c ColumnModel
+	c.SetSize(5, 4)
+
+	tcs := []struct {
+		name        string
+		first, last int
+		expected    string
+	}{
+		{name: "top", first: 0, last: 0, expected: "abcd"},
+		{name: "selected line below", first: 5, last: 6, expected: "defg"},
+		{name: "selected line visible", first: 4, last: 4, expected: "defg"},
+		{name: "selected line above", first: 1, last: 1, expected: "bcde"},
+		{name: "selection taller than the column", first: 2, last: 9, expected: "cdef"},
+		{name: "past the end", first: 20, last: 20, expected: "ghij"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			view := c.Render(style, content, tc.first, tc.last)
+			visible := strings.ReplaceAll(strings.ReplaceAll(view, " ", ""), "\n", "")
+			if visible != tc.expected {
+				t.Errorf("expected lines %s, got %q", tc.expected, view)
+			}
+			if lipgloss.Height(view) != 4 || lipgloss.Width(view) != 5 {
+				t.Errorf("expected a 5x4 view, got %dx%d", lipgloss.Width(view), lipgloss.Height(view))
+			}
+		})
+	}
+}
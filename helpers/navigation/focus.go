@@ -11,4 +11,9 @@ type FormField interface {
 	tea.Model
 	Focusable
 	Value() string
+	// Values returns the values chosen in the field, a single value field returns at most one
+	Values() []string
+	SetValidators(validators ...Validator)
+	// Validate returns the error of the first validator rejecting the value of the field
+	Validate() error
 }
@@ -0,0 +1,94 @@
+package navigation
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/xsevy/terrapi/i18n"
+)
+
+// awsNameRegex accepts names valid as terraform identifiers, lambda functions, IAM roles and AppSync data sources
+var awsNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// s3KeyRegex accepts the characters safe in S3 object keys and in the generated backend configuration,
+// with the colon of the default workspace key prefix env:
+var s3KeyRegex = regexp.MustCompile(`^[A-Za-z0-9!_.*()/:-]*$`)
+
+// Validator checks the value of a form field, the error tells the user what is wrong with it
+type Validator func(value string) error
+
+// Validation holds the validators of a form field, the form fields embed it
+type Validation struct {
+	validators []Validator
+}
+
+// SetValidators replaces the validators of the field
+func (v *Validation) SetValidators(validators ...Validator) {
+	v.validators = validators
+}
+
+// Check returns the error of the first validator rejecting value
+func (v *Validation) Check(value string) error {
+	for _, validator := range v.validators {
+		if err := validator(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Required rejects an empty value. The other validators accept it, so optional fields can use them.
+func Required() Validator {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return errors.New(i18n.T("validation.required"))
+		}
+		return nil
+	}
+}
+
+// Pattern rejects a value not matching regex with message
+func Pattern(regex *regexp.Regexp, message string) Validator {
+	return func(value string) error {
+		if value != "" && !regex.MatchString(value) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// AWSName rejects a value which can not be used in the names of the generated resources
+func AWSName() Validator {
+	return Pattern(awsNameRegex, i18n.T("validation.aws_name"))
+}
+
+// S3Key rejects a value which can not be used as the key of a terraform state or its prefix
+func S3Key() Validator {
+	return func(value string) error {
+		switch {
+		case value != "" && strings.TrimSpace(value) == "":
+			return errors.New(i18n.T("validation.s3_key_blank"))
+		case strings.HasPrefix(value, "/"):
+			return errors.New(i18n.T("validation.s3_key_leading_slash"))
+		case strings.HasSuffix(value, "/") || strings.Contains(value, "//"):
+			return errors.New(i18n.T("validation.s3_key_empty_part"))
+		case !s3KeyRegex.MatchString(value):
+			return errors.New(i18n.T("validation.s3_key_characters"))
+		}
+		return nil
+	}
+}
+
+// Unique rejects a value returned by existing, which is called on every validation
+// as the existing values can depend on other fields
+func Unique(existing func() []string) Validator {
+	return func(value string) error {
+		for _, e := range existing() {
+			if value != "" && value == e {
+				return errors.New(i18n.T("validation.exists", value))
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package navigation
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tcs := []struct {
+		name      string
+		validator Validator
+		value     string
+		expected  string
+	}{
+		{name: "required empty", validator: Required(), value: " ", expected: "required"},
+		{name: "required", validator: Required(), value: "users"},
+		{name: "pattern", validator: Pattern(regexp.MustCompile(`^\d+$`), "digits"), value: "12"},
+		{name: "pattern mismatch", validator: Pattern(regexp.MustCompile(`^\d+$`), "digits"), value: "a", expected: "digits"},
+		{name: "pattern empty", validator: Pattern(regexp.MustCompile(`^\d+$`), "digits"), value: ""},
+		{name: "aws name", validator: AWSName(), value: "user_posts2"},
+		{name: "aws name with space", validator: AWSName(), value: "user posts", expected: "only letters, digits and underscores, not starting with a digit"},
+		{name: "aws name with dash", validator: AWSName(), value: "user-posts", expected: "only letters, digits and underscores, not starting with a digit"},
+		{name: "aws name starting with digit", validator: AWSName(), value: "2posts", expected: "only letters, digits and underscores, not starting with a digit"},
+		{name: "s3 key", validator: S3Key(), value: "blog/prod/terraform.tfstate"},
+		{name: "s3 key prefix", validator: S3Key(), value: "env:"},
+		{name: "s3 key empty", validator: S3Key(), value: ""},
+		{name: "s3 key blank", validator: S3Key(), value: "  ", expected: "must not be blank"},
+		{name: "s3 key leading slash", validator: S3Key(), value: "/blog/terraform.tfstate", expected: "must not start with /"},
+		{name: "s3 key trailing slash", validator: S3Key(), value: "blog/", expected: "must not end with / or contain //"},
+		{name: "s3 key empty part", validator: S3Key(), value: "blog//terraform.tfstate", expected: "must not end with / or contain //"},
+		{name: "s3 key quote", validator: S3Key(), value: `blog"/terraform.tfstate`, expected: "only letters, digits, / and the characters ! - _ . * ( ) :"},
+		{name: "s3 key apostrophe", validator: S3Key(), value: "blog's/terraform.tfstate", expected: "only letters, digits, / and the characters ! - _ . * ( ) :"},
+		{name: "s3 key ampersand", validator: S3Key(), value: "a&b/terraform.tfstate", expected: "only letters, digits, / and the characters ! - _ . * ( ) :"},
+		{name: "s3 key space", validator: S3Key(), value: "my blog/terraform.tfstate", expected: "only letters, digits, / and the characters ! - _ . * ( ) :"},
+		{name: "unique", validator: Unique(func() []string { return []string{"posts"} }), value: "users"},
+		{name: "not unique", validator: Unique(func() []string { return []string{"posts"} }), value: "posts", expected: "posts already exists"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.validator(tc.value)
+			if tc.expected == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if tc.expected != "" && (err == nil || err.Error() != tc.expected) {
+				t.Errorf("expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidationCheck(t *testing.T) {
+	var v Validation
+	if err := v.Check(""); err != nil {
+		t.Errorf("expected no error without validators, got %v", err)
+	}
+
+	v.SetValidators(Required(), AWSName())
+	if err := v.Check(""); err == nil || err.Error() != "required" {
+		t.Errorf("expected the error of the first validator, got %v", err)
+	}
+	if err := v.Check("a b"); err == nil {
+		t.Error("expected an error from the second validator, got nil")
+	}
+}
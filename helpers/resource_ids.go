@@ -1,16 +1,26 @@
 package helpers
 
+import "github.com/xsevy/terrapi/i18n"
+
 type resourceIDs struct {
 	CreateAppSyncDataSource string
 	CreateAppSyncAPI        string
+	ImportAppSyncAPI        string
+	CheckProject            string
+	DriftReport             string
+	ProjectDashboard        string
 }
 
 var ResourceIDs = resourceIDs{
 	CreateAppSyncDataSource: "create_app_sync_data_source",
 	CreateAppSyncAPI:        "create_app_sync_api",
+	ImportAppSyncAPI:        "import_app_sync_api",
+	CheckProject:            "check_project",
+	DriftReport:             "drift_report",
+	ProjectDashboard:        "project_dashboard",
 }
 
-var ResourceNames = map[string]string{
-	ResourceIDs.CreateAppSyncDataSource: "Create data source",
-	ResourceIDs.CreateAppSyncAPI:        "Create API",
+// ResourceName returns the name of a resource in the current language
+func ResourceName(id string) string {
+	return i18n.T("resource." + id)
 }
@@ -0,0 +1,181 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the language of the keys, it is used for the keys missing in the other catalogs
+const DefaultLocale = "en"
+
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+// message is a translation, plural messages have one form per plural category
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+func (m *message) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&m.text)
+	}
+	return node.Decode(&m.plural)
+}
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]message{}
+	locale   = DefaultLocale
+)
+
+func init() {
+	if err := Load(localeFiles, "locales"); err != nil {
+		panic(err)
+	}
+}
+
+// Load adds the catalogs <locale>.yaml found in dir of fsys, so packages like template packs
+// can ship their own translations. Keys already loaded are replaced.
+func Load(fsys fs.FS, dir string) error {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		var messages map[string]message
+		if err := yaml.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("unable to read the catalog %s: %w", p, err)
+		}
+
+		name := strings.TrimSuffix(path.Base(p), ".yaml")
+		if catalogs[name] == nil {
+			catalogs[name] = map[string]message{}
+		}
+		for key, m := range messages {
+			catalogs[name][key] = m
+		}
+	}
+
+	return nil
+}
+
+// Locales returns the languages having a catalog
+func Locales() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(catalogs))
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect returns the language to use, the first one set of flag, $LC_ALL, $LC_MESSAGES and $LANG.
+// Languages without a catalog give DefaultLocale.
+func Detect(flag string) string {
+	for _, value := range []string{flag, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if value == "" {
+			continue
+		}
+		// pl_PL.UTF-8 and pl-PL name the pl language
+		language, _, _ := strings.Cut(value, ".")
+		language, _, _ = strings.Cut(language, "_")
+		language, _, _ = strings.Cut(language, "-")
+		language = strings.ToLower(language)
+
+		mu.RLock()
+		_, ok := catalogs[language]
+		mu.RUnlock()
+		if ok {
+			return language
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// SetLocale sets the language of T and N
+func SetLocale(l string) {
+	mu.Lock()
+	defer mu.Unlock()
+	locale = l
+}
+
+// Locale returns the language of T and N
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return locale
+}
+
+// T returns the translation of key formatted with args, the key itself when it has no translation
+func T(key string, args ...any) string {
+	m, ok := lookup(key)
+	if !ok {
+		return format(key, args)
+	}
+	if m.plural != nil {
+		return format(m.plural[general(Locale())], args)
+	}
+	return format(m.text, args)
+}
+
+// N returns the plural form of key for the count n formatted with args, or with n when args are empty
+func N(key string, n int, args ...any) string {
+	if len(args) == 0 {
+		args = []any{n}
+	}
+
+	m, ok := lookup(key)
+	if !ok {
+		return format(key, args)
+	}
+	if m.plural == nil {
+		return format(m.text, args)
+	}
+
+	text, ok := m.plural[category(Locale(), n)]
+	if !ok {
+		text = m.plural[general(Locale())]
+	}
+	return format(text, args)
+}
+
+// lookup finds the message of key in the current language, then in DefaultLocale
+func lookup(key string) (message, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if m, ok := catalogs[locale][key]; ok {
+		return m, true
+	}
+	m, ok := catalogs[DefaultLocale][key]
+	return m, ok
+}
+
+func format(text string, args []any) string {
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
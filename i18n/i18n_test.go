@@ -0,0 +1,237 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// catalogDirs are the catalogs shipped in the repository, relative to this package
+var catalogDirs = []string{"../templates"}
+
+var (
+	// keyRegex matches the string literals of the sources, the keys are checked when their first part names a catalog section
+	keyRegex  = regexp.MustCompile(`"([a-z_]+\.[a-z0-9_.]*)"`)
+	verbRegex = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+)
+
+func setLocale(t *testing.T, l string) {
+	t.Helper()
+	previous := Locale()
+	SetLocale(l)
+	t.Cleanup(func() { SetLocale(previous) })
+}
+
+func loadCatalogs(t *testing.T) {
+	t.Helper()
+	for _, dir := range catalogDirs {
+		if err := Load(os.DirFS(dir), "locales"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	setLocale(t, "pl")
+
+	if got := T("validation.exists", "users"); got != "users już istnieje" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := T("missing.key"); got != "missing.key" {
+		t.Errorf("expected the key without a translation, got %q", got)
+	}
+	if got := T("drift.differences"); got != "%d różnic" {
+		t.Errorf("expected the general plural form, got %q", got)
+	}
+}
+
+func TestN(t *testing.T) {
+	tcs := []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{"en", 0, "0 differences"},
+		{"en", 1, "1 difference"},
+		{"en", 2, "2 differences"},
+		{"pl", 1, "1 różnica"},
+		{"pl", 3, "3 różnice"},
+		{"pl", 5, "5 różnic"},
+		{"pl", 12, "12 różnic"},
+		{"pl", 22, "22 różnice"},
+		{"pl", 112, "112 różnic"},
+		{"de", 1, "1 difference"},
+	}
+	for _, tc := range tcs {
+		setLocale(t, tc.locale)
+		if got := N("drift.differences", tc.n); got != tc.want {
+			t.Errorf("%s %d: expected %q, got %q", tc.locale, tc.n, tc.want, got)
+		}
+	}
+
+	setLocale(t, "en")
+	if got := N("check.summary", 2, 2, "1 error, 1 warning"); got != "2 issues: 1 error, 1 warning" {
+		t.Errorf("unexpected message with arguments %q", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tcs := []struct {
+		name  string
+		flag  string
+		lcAll string
+		lang  string
+		want  string
+	}{
+		{name: "nothing set", want: DefaultLocale},
+		{name: "flag", flag: "pl", lang: "en_US.UTF-8", want: "pl"},
+		{name: "lang with territory and encoding", lang: "pl_PL.UTF-8", want: "pl"},
+		{name: "lc_all before lang", lcAll: "en_GB", lang: "pl_PL", want: "en"},
+		{name: "unsupported language", lang: "de_DE.UTF-8", want: DefaultLocale},
+		{name: "tag", flag: "PL-pl", want: "pl"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tc.lcAll)
+			t.Setenv("LC_MESSAGES", "")
+			t.Setenv("LANG", tc.lang)
+
+			if got := Detect(tc.flag); got != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	setLocale(t, "pl")
+	pack := fstest.MapFS{
+		"locales/en.yaml": {Data: []byte("pack.greeting: \"Hello %s\"\n")},
+		"locales/pl.yaml": {Data: []byte("pack.greeting: \"Cześć %s\"\n")},
+	}
+
+	if err := Load(pack, "locales"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := T("pack.greeting", "Ala"); got != "Cześć Ala" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := T("validation.required"); got != "wymagane" {
+		t.Errorf("expected the catalog of the pack to be added, got %q", got)
+	}
+
+	broken := fstest.MapFS{"locales/en.yaml": {Data: []byte("pack: [")}}
+	if err := Load(broken, "locales"); err == nil {
+		t.Error("expected an error for an invalid catalog")
+	}
+}
+
+// TestUntranslatedKeys fails on the keys used in the sources which are missing in the catalog of DefaultLocale
+func TestUntranslatedKeys(t *testing.T) {
+	loadCatalogs(t)
+
+	sections := map[string]bool{}
+	for key := range catalogs[DefaultLocale] {
+		section, _, _ := strings.Cut(key, ".")
+		sections[section] = true
+	}
+
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && d.Name() != ".." {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || filepath.Ext(path) != ".go" || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyRegex.FindAllStringSubmatch(string(content), -1) {
+			key := match[1]
+			section, _, _ := strings.Cut(key, ".")
+			if !sections[section] {
+				continue
+			}
+			if !hasKey(catalogs[DefaultLocale], key) {
+				t.Errorf("%s: %s has no translation in %s", path, key, DefaultLocale)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestCatalogs checks that every locale translates every key, with the plural forms of the language
+// and the fmt verbs of DefaultLocale
+func TestCatalogs(t *testing.T) {
+	loadCatalogs(t)
+
+	base := catalogs[DefaultLocale]
+	for _, locale := range Locales() {
+		catalog := catalogs[locale]
+		for key, want := range base {
+			got, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %s", locale, key)
+				continue
+			}
+
+			if (got.plural == nil) != (want.plural == nil) {
+				t.Errorf("%s: %s has to be plural in every locale or in none", locale, key)
+				continue
+			}
+
+			verbs := verbRegex.FindAllString(want.text, -1)
+			forms := []string{got.text}
+			if want.plural != nil {
+				verbs = verbRegex.FindAllString(want.plural[general(DefaultLocale)], -1)
+				forms = nil
+				for _, category := range Categories(locale) {
+					form, ok := got.plural[category]
+					if !ok {
+						t.Errorf("%s: %s has no %s form", locale, key, category)
+					}
+					forms = append(forms, form)
+				}
+			}
+
+			for _, form := range forms {
+				if got := verbRegex.FindAllString(form, -1); !slices.Equal(got, verbs) {
+					t.Errorf("%s: %s uses the verbs %v instead of %v", locale, key, got, verbs)
+				}
+			}
+		}
+
+		for key := range catalog {
+			if _, ok := base[key]; !ok {
+				t.Errorf("%s: %s is not in %s", locale, key, DefaultLocale)
+			}
+		}
+	}
+}
+
+// hasKey reports whether catalog has key, a key ending with a dot is a prefix completed at run time
+func hasKey(catalog map[string]message, key string) bool {
+	if !strings.HasSuffix(key, ".") {
+		_, ok := catalog[key]
+		return ok
+	}
+	for k := range catalog {
+		if strings.HasPrefix(k, key) {
+			return true
+		}
+	}
+	return false
+}
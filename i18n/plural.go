@@ -0,0 +1,64 @@
+package i18n
+
+// plural categories, as named by the CLDR plural rules
+const (
+	one   = "one"
+	few   = "few"
+	many  = "many"
+	other = "other"
+)
+
+// pluralRule lists the plural categories of a language and chooses the one of a count
+type pluralRule struct {
+	categories []string
+	category   func(n int) string
+}
+
+// defaultRule is the rule of english and of the languages without their own rule
+var defaultRule = pluralRule{
+	categories: []string{one, other},
+	category: func(n int) string {
+		if n == 1 {
+			return one
+		}
+		return other
+	},
+}
+
+var pluralRules = map[string]pluralRule{
+	"pl": {
+		categories: []string{one, few, many},
+		category: func(n int) string {
+			switch {
+			case n == 1:
+				return one
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return few
+			default:
+				return many
+			}
+		},
+	},
+}
+
+// Categories lists the plural categories a catalog of the language has to define
+func Categories(language string) []string {
+	return rule(language).categories
+}
+
+func category(language string, n int) string {
+	return rule(language).category(n)
+}
+
+// general is the category used without a count, the last one of the language
+func general(language string) string {
+	categories := rule(language).categories
+	return categories[len(categories)-1]
+}
+
+func rule(language string) pluralRule {
+	if r, ok := pluralRules[language]; ok {
+		return r
+	}
+	return defaultRule
+}
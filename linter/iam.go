@@ -0,0 +1,67 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const wildcardIAMRule = "wildcard-iam"
+
+var (
+	// matches both HCL (actions = [...]) and JSON ("Action": [...]) statement keys
+	iamKeyRegex     = regexp.MustCompile(`^\s*"?(actions|resources|not_actions|not_resources|Action|Resource|NotAction|NotResource)"?\s*[:=]\s*(.*)$`)
+	quotedRegex     = regexp.MustCompile(`"([^"]*)"`)
+	serviceWildcard = regexp.MustCompile(`^[a-z0-9-]+:\*$`)
+)
+
+// wildcardIAM flags IAM statements granting every action of a service or access to every resource
+func wildcardIAM(files []file) []Issue {
+	var issues []Issue
+	for _, f := range files {
+		issues = append(issues, wildcardIAMInFile(f.path, f.lines)...)
+	}
+
+	return issues
+}
+
+func wildcardIAMInFile(path string, lines []string) []Issue {
+	var issues []Issue
+
+	for i := 0; i < len(lines); i++ {
+		match := iamKeyRegex.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		isAction := strings.Contains(strings.ToLower(match[1]), "action")
+
+		// values of a multiline list are spread over the following lines
+		values := map[int]string{i: match[2]}
+		if strings.HasPrefix(strings.TrimSpace(match[2]), "[") && !strings.Contains(match[2], "]") {
+			for i+1 < len(lines) {
+				i++
+				values[i] = lines[i]
+				if strings.Contains(lines[i], "]") {
+					break
+				}
+			}
+		}
+
+		for line, value := range values {
+			for _, quoted := range quotedRegex.FindAllStringSubmatch(value, -1) {
+				v := quoted[1]
+				if v == "*" || (isAction && serviceWildcard.MatchString(v)) {
+					issues = append(issues, Issue{
+						File:     path,
+						Line:     line + 1,
+						Rule:     wildcardIAMRule,
+						Severity: SeverityError,
+						Message:  fmt.Sprintf("wildcard %q in IAM statement %s", v, match[1]),
+					})
+				}
+			}
+		}
+	}
+
+	return issues
+}
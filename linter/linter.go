@@ -0,0 +1,145 @@
+package linter
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Severity tells how serious an issue is
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = map[Severity]string{
+	SeverityInfo:    "info",
+	SeverityWarning: "warning",
+	SeverityError:   "error",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity with the given name
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if n == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+
+	return SeverityInfo, fmt.Errorf("unknown severity %s", name)
+}
+
+// Issue is a single problem found in a project file
+type Issue struct {
+	File     string
+	Line     int
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s [%s] %s", i.File, i.Line, i.Severity, i.Rule, i.Message)
+}
+
+// file is a project file read by Lint
+type file struct {
+	path  string
+	lines []string
+}
+
+// rule inspects the project files and returns the issues found in them
+type rule func(files []file) []Issue
+
+var rules = []rule{
+	wildcardIAM,
+	lambdaLogRetention,
+	appSyncObservability,
+	perpetualDiff,
+	missingTags,
+	unpinnedProviders,
+}
+
+var lintedExtensions = map[string]bool{
+	".tf":   true,
+	".json": true,
+}
+
+// Lint walks the project and returns the issues found, ordered by file and line
+func Lint(fsys fs.FS) ([]Issue, error) {
+	var files []file
+
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != "." && d.Name()[0] == '.' {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !lintedExtensions[filepath.Ext(path)] {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, file{path: path, lines: strings.Split(string(content), "\n")})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, r := range rules {
+		issues = append(issues, r(files)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+
+	return issues, nil
+}
+
+// Exceeds reports whether any of the issues is at least as severe as the threshold
+func Exceeds(issues []Issue, threshold Severity) bool {
+	for _, issue := range issues {
+		if issue.Severity >= threshold {
+			return true
+		}
+	}
+
+	return false
+}
+
+// terraformFiles returns only the files written in HCL
+func terraformFiles(files []file) []file {
+	var tf []file
+	for _, f := range files {
+		if filepath.Ext(f.path) == ".tf" {
+			tf = append(tf, f)
+		}
+	}
+
+	return tf
+}
@@ -0,0 +1,267 @@
+package linter
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestWildcardIAM(t *testing.T) {
+	tcs := []struct {
+		name          string
+		fsys          fstest.MapFS
+		expectedLines []int
+	}{
+		{
+			name: "HCL wildcard resource",
+			fsys: fstest.MapFS{
+				"iam.tf": {Data: []byte(`data "aws_iam_policy_document" "doc" {
+  statement {
+    actions   = ["lambda:InvokeFunction"]
+    resources = ["*"]
+  }
+}`)},
+			},
+			expectedLines: []int{4},
+		},
+		{
+			name: "HCL multiline wildcard action",
+			fsys: fstest.MapFS{
+				"iam.tf": {Data: []byte(`data "aws_iam_policy_document" "doc" {
+  statement {
+    actions = [
+      "lambda:GetFunction",
+      "s3:*",
+    ]
+    resources = [aws_s3_bucket.bucket.arn]
+  }
+}`)},
+			},
+			expectedLines: []int{5},
+		},
+		{
+			name: "JSON wildcard action and resource",
+			fsys: fstest.MapFS{
+				"policy.json": {Data: []byte(`{
+  "Statement": [
+    {
+      "Action": "*",
+      "Resource": "*",
+      "Effect": "Allow"
+    }
+  ]
+}`)},
+			},
+			expectedLines: []int{4, 5},
+		},
+		{
+			name: "Scoped statements",
+			fsys: fstest.MapFS{
+				"iam.tf": {Data: []byte(`data "aws_iam_policy_document" "doc" {
+  statement {
+    actions   = ["lambda:InvokeFunction"]
+    resources = [module.x.lambda_function_arn, "${module.x.lambda_function_arn}:*"]
+  }
+}`)},
+				"policy.json": {Data: []byte(`{"Action": ["logs:PutLogEvents"], "Resource": "arn:aws:logs:*:*:log-group:/aws/lambda/x:*"}`)},
+			},
+			expectedLines: nil,
+		},
+		{
+			name: "Not linted files",
+			fsys: fstest.MapFS{
+				"README.md":          {Data: []byte(`resources = ["*"]`)},
+				".terraform/main.tf": {Data: []byte(`resources = ["*"]`)},
+			},
+			expectedLines: nil,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, err := Lint(tc.fsys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(issues) != len(tc.expectedLines) {
+				t.Fatalf("expected %d issues, got %d: %v", len(tc.expectedLines), len(issues), issues)
+			}
+
+			for i, issue := range issues {
+				if issue.Rule != wildcardIAMRule {
+					t.Errorf("expected rule %s, got %s", wildcardIAMRule, issue.Rule)
+				}
+				if issue.Line != tc.expectedLines[i] {
+					t.Errorf("expected line %d, got %d", tc.expectedLines[i], issue.Line)
+				}
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	tcs := []struct {
+		name          string
+		fsys          fstest.MapFS
+		expectedRules []string
+	}{
+		{
+			name: "Lambda without log retention",
+			fsys: fstest.MapFS{
+				"ds/lambda.tf": {Data: []byte(`resource "aws_lambda_function" "lambda_function" {
+  function_name = "x"
+  tags          = {}
+}`)},
+			},
+			expectedRules: []string{lambdaLogRetentionRule},
+		},
+		{
+			name: "Lambda with log retention in the same module",
+			fsys: fstest.MapFS{
+				"ds/lambda.tf": {Data: []byte(`resource "aws_lambda_function" "lambda_function" {
+  function_name = "x"
+  tags          = {}
+}`)},
+				"ds/logs.tf": {Data: []byte(`resource "aws_cloudwatch_log_group" "logs" {
+  name              = "/aws/lambda/x"
+  retention_in_days = 14
+  tags              = {}
+}`)},
+			},
+			expectedRules: nil,
+		},
+		{
+			name: "AppSync without logging and X-Ray",
+			fsys: fstest.MapFS{
+				"appsync.tf": {Data: []byte(`resource "aws_appsync_graphql_api" "appsync" {
+  name = "${local.project_name}_appsync"
+  tags = {}
+
+  lambda_authorizer_config {
+    authorizer_uri = data.aws_lambda_function.authorizer.arn
+  }
+}`)},
+			},
+			expectedRules: []string{appSyncLoggingRule, appSyncXRayRule},
+		},
+		{
+			name: "AppSync with logging and X-Ray",
+			fsys: fstest.MapFS{
+				"appsync.tf": {Data: []byte(`resource "aws_appsync_graphql_api" "appsync" {
+  name         = "${local.project_name}_appsync"
+  xray_enabled = true
+  tags         = {}
+
+  log_config {
+    field_log_level = "ERROR"
+  }
+}`)},
+			},
+			expectedRules: nil,
+		},
+		{
+			name: "Timestamp trigger",
+			fsys: fstest.MapFS{
+				"lambda.tf": {Data: []byte(`resource "null_resource" "install_dependencies" {
+  triggers = {
+    always_run = "${timestamp()}"
+  }
+}`)},
+			},
+			expectedRules: []string{perpetualDiffRule},
+		},
+		{
+			name: "Missing tags",
+			fsys: fstest.MapFS{
+				"iam.tf": {Data: []byte(`resource "aws_iam_role" "role" {
+  name = "role"
+}`)},
+			},
+			expectedRules: []string{missingTagsRule},
+		},
+		{
+			name: "Default tags",
+			fsys: fstest.MapFS{
+				"iam.tf": {Data: []byte(`resource "aws_iam_role" "role" {
+  name = "role"
+}`)},
+				"provider.tf": {Data: []byte(`provider "aws" {
+  region = "eu-central-1"
+
+  default_tags {
+    tags = {
+      Project = "x"
+    }
+  }
+}`)},
+			},
+			expectedRules: nil,
+		},
+		{
+			name: "Unpinned providers",
+			fsys: fstest.MapFS{
+				"main.tf": {Data: []byte(`terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+    null = {
+      source = "hashicorp/null"
+    }
+    archive = {
+      source  = "hashicorp/archive"
+      version = ">= 2.0"
+    }
+  }
+}`)},
+			},
+			expectedRules: []string{unpinnedProviderRule, unpinnedProviderRule},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			issues, err := Lint(tc.fsys)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(issues) != len(tc.expectedRules) {
+				t.Fatalf("expected %d issues, got %d: %v", len(tc.expectedRules), len(issues), issues)
+			}
+
+			for i, issue := range issues {
+				if issue.Rule != tc.expectedRules[i] {
+					t.Errorf("expected rule %s, got %s", tc.expectedRules[i], issue.Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	issues := []Issue{{Severity: SeverityInfo}, {Severity: SeverityWarning}}
+
+	if !Exceeds(issues, SeverityWarning) {
+		t.Error("expected warning to exceed the warning threshold")
+	}
+
+	if Exceeds(issues, SeverityError) {
+		t.Error("expected warning not to exceed the error threshold")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := ParseSeverity("Warning")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s != SeverityWarning {
+		t.Errorf("expected %s, got %s", SeverityWarning, s)
+	}
+
+	if _, err := ParseSeverity("critical"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,233 @@
+package linter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/xsevy/terrapi/helpers/hcl"
+)
+
+const (
+	lambdaLogRetentionRule = "lambda-log-retention"
+	appSyncLoggingRule     = "appsync-logging"
+	appSyncXRayRule        = "appsync-xray"
+	perpetualDiffRule      = "perpetual-diff"
+	missingTagsRule        = "missing-tags"
+	unpinnedProviderRule   = "unpinned-provider"
+)
+
+var (
+	timestampRegex = regexp.MustCompile(`\btimestamp\(\)`)
+
+	// resource types created by terrapi templates which accept tags
+	taggableResources = map[string]bool{
+		"aws_appsync_graphql_api":  true,
+		"aws_cloudwatch_log_group": true,
+		"aws_iam_policy":           true,
+		"aws_iam_role":             true,
+		"aws_lambda_function":      true,
+	}
+)
+
+// resources returns the resource blocks of the given type found in the files, by file
+func resources(files []file, typ string) map[string][]hcl.Block {
+	found := map[string][]hcl.Block{}
+	for _, f := range terraformFiles(files) {
+		for _, b := range hcl.Parse(f.lines) {
+			if b.Type == "resource" && (typ == "" || b.Label(0) == typ) {
+				found[f.path] = append(found[f.path], b)
+			}
+		}
+	}
+
+	return found
+}
+
+// lambdaLogRetention flags Lambda functions without a log group with retention in the same module,
+// otherwise Lambda creates the log group itself and keeps the logs forever
+func lambdaLogRetention(files []file) []Issue {
+	var issues []Issue
+
+	retainedDirs := map[string]bool{}
+	for path, blocks := range resources(files, "aws_cloudwatch_log_group") {
+		for _, b := range blocks {
+			if _, _, ok := b.Attribute("retention_in_days"); ok {
+				retainedDirs[filepath.Dir(path)] = true
+			}
+		}
+	}
+
+	for path, blocks := range resources(files, "aws_lambda_function") {
+		if retainedDirs[filepath.Dir(path)] {
+			continue
+		}
+		for _, b := range blocks {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     b.Line + 1,
+				Rule:     lambdaLogRetentionRule,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("lambda function %s has no log group with retention_in_days", b.Label(1)),
+			})
+		}
+	}
+
+	return issues
+}
+
+// appSyncObservability flags AppSync APIs without logging or X-Ray tracing
+func appSyncObservability(files []file) []Issue {
+	var issues []Issue
+
+	for path, blocks := range resources(files, "aws_appsync_graphql_api") {
+		for _, b := range blocks {
+			hasLogConfig := false
+			for _, child := range b.Children() {
+				if child.Type == "log_config" {
+					hasLogConfig = true
+				}
+			}
+			if !hasLogConfig {
+				issues = append(issues, Issue{
+					File:     path,
+					Line:     b.Line + 1,
+					Rule:     appSyncLoggingRule,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("appsync api %s has no log_config", b.Label(1)),
+				})
+			}
+
+			if value, _, _ := b.Attribute("xray_enabled"); value != "true" {
+				issues = append(issues, Issue{
+					File:     path,
+					Line:     b.Line + 1,
+					Rule:     appSyncXRayRule,
+					Severity: SeverityInfo,
+					Message:  fmt.Sprintf("appsync api %s has xray_enabled disabled", b.Label(1)),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// perpetualDiff flags timestamp() calls, which change on every run so the plan is never empty
+func perpetualDiff(files []file) []Issue {
+	var issues []Issue
+
+	for _, f := range terraformFiles(files) {
+		for i, line := range f.lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") || !timestampRegex.MatchString(line) {
+				continue
+			}
+			issues = append(issues, Issue{
+				File:     f.path,
+				Line:     i + 1,
+				Rule:     perpetualDiffRule,
+				Severity: SeverityWarning,
+				Message:  "timestamp() changes on every run and causes a perpetual diff, use a content hash instead",
+			})
+		}
+	}
+
+	return issues
+}
+
+// missingTags flags taggable resources without tags, unless the aws provider sets default_tags
+func missingTags(files []file) []Issue {
+	var issues []Issue
+
+	for _, f := range terraformFiles(files) {
+		for _, b := range hcl.Parse(f.lines) {
+			if b.Type != "provider" || b.Label(0) != "aws" {
+				continue
+			}
+			for _, child := range b.Children() {
+				if child.Type == "default_tags" {
+					return nil
+				}
+			}
+		}
+	}
+
+	for path, blocks := range resources(files, "") {
+		for _, b := range blocks {
+			if !taggableResources[b.Label(0)] {
+				continue
+			}
+			if _, _, ok := b.Attribute("tags"); ok {
+				continue
+			}
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     b.Line + 1,
+				Rule:     missingTagsRule,
+				Severity: SeverityInfo,
+				Message:  fmt.Sprintf("%s.%s has no tags and the aws provider has no default_tags", b.Label(0), b.Label(1)),
+			})
+		}
+	}
+
+	return issues
+}
+
+// unpinnedProviders flags providers in main.tf without an upper bound on their version
+func unpinnedProviders(files []file) []Issue {
+	var issues []Issue
+
+	for _, f := range terraformFiles(files) {
+		if filepath.Base(f.path) != "main.tf" {
+			continue
+		}
+
+		for _, b := range hcl.Parse(f.lines) {
+			if b.Type != "terraform" {
+				continue
+			}
+			for _, child := range b.Children() {
+				if child.Type != "required_providers" {
+					continue
+				}
+				for _, provider := range child.Objects() {
+					version, _, ok := provider.Attribute("version")
+					if ok && isPinned(version) {
+						continue
+					}
+					issues = append(issues, Issue{
+						File:     f.path,
+						Line:     provider.Line + 1,
+						Rule:     unpinnedProviderRule,
+						Severity: SeverityWarning,
+						Message:  fmt.Sprintf("provider %s version is not pinned, use a constraint like \"~> 5.0\"", provider.Type),
+					})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// isPinned reports whether a version constraint has an upper bound
+func isPinned(constraint string) bool {
+	constraint = strings.Trim(constraint, `"`)
+	if constraint == "" {
+		return false
+	}
+
+	for _, c := range strings.Split(constraint, ",") {
+		c = strings.TrimSpace(c)
+		if strings.HasPrefix(c, "~>") || strings.HasPrefix(c, "<") {
+			return true
+		}
+		if !strings.ContainsAny(c, "<>!~") {
+			// exact version like "5.0.0" or "= 5.0.0"
+			return true
+		}
+	}
+
+	return false
+}
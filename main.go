@@ -1,39 +1,127 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/xsevy/terrapi/aws"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/xsevy/terrapi/aws/cache"
+	"github.com/xsevy/terrapi/aws/fake"
+	"github.com/xsevy/terrapi/commands"
+	"github.com/xsevy/terrapi/config"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/i18n"
 	"github.com/xsevy/terrapi/models/main_model"
-	"github.com/xsevy/terrapi/models/menu"
-	"github.com/xsevy/terrapi/models/select_column"
-	"github.com/xsevy/terrapi/models/select_column_choices"
-	"github.com/xsevy/terrapi/models/setup_column"
+	"github.com/xsevy/terrapi/styles"
 )
 
 func main() {
-	awsClient := aws.NewAWS()
-	lambdaClient := aws.NewLambda(awsClient)
-	appsyncClient := aws.NewAppSync(awsClient)
-	s3Client := aws.NewS3(awsClient)
-	dynamoDBClient := aws.NewDynamoDB(awsClient)
-
-	selectColumnChoices := select_column_choices.NewSelectColumnChoicesModel()
-	selectColumn := select_column.NewSelectColumnModel(selectColumnChoices, true)
-	setup_column := setup_column.NewSetupColumnModel(
-		lambdaClient,
-		appsyncClient,
-		s3Client,
-		dynamoDBClient,
-		false,
-	)
-	menu := menu.NewMenuModel(selectColumn, setup_column)
-	main := main_model.NewMainModel(menu)
-
-	p := tea.NewProgram(main)
-	_, err := p.Run()
+	// the flags are parsed once to find -lang, the usage of the flags is then shown in its language
+	probe, probed := newFlagSet(flag.ContinueOnError)
+	probe.SetOutput(io.Discard)
+	probe.Parse(os.Args[1:])
+	i18n.SetLocale(i18n.Detect(probed.lang))
+
+	flags, opts := newFlagSet(flag.ExitOnError)
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() > 0 {
+		os.Exit(commands.Run(flags.Args(), os.Stdout, os.Stderr))
+	}
+
+	// the configuration is applied before the models are created, they copy the keys, their help and the styles
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	cfg, err := config.Load(configPath)
 	if err != nil {
 		log.Fatalln(err)
 	}
+	helpers.Keys = cfg.Keys
+	styles.SetTheme(cfg.Theme)
+	if cfg.NoColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	// the AWS requests are cancelled when the program is terminated
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var clients main_model.Clients
+	switch {
+	case opts.fixture != "":
+		account, err := fake.Load(opts.fixture)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		clients = fakeClients(account)
+	case opts.demo:
+		clients = fakeClients(fake.Demo())
+	default:
+		var cacheDir string
+		if !opts.noCache {
+			if cacheDir, err = cache.DefaultDir(); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		ttl := cfg.AWS.CacheTTL
+		if ttl == 0 {
+			ttl = cache.DefaultTTL
+		}
+		clients, err = main_model.NewClients(ctx, cacheDir, ttl, cfg.AWS.Options()...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	p := tea.NewProgram(main_model.NewApp(ctx, clients, cfg.AWS.ProductionAccounts), tea.WithContext(ctx))
+	_, err = p.Run()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// options are the flags of the interactive mode
+type options struct {
+	lang    string
+	demo    bool
+	fixture string
+	noCache bool
+}
+
+// newFlagSet defines the flags with their usage in the current locale
+func newFlagSet(errorHandling flag.ErrorHandling) (*flag.FlagSet, *options) {
+	var o options
+	flags := flag.NewFlagSet("terrapi", errorHandling)
+	flags.StringVar(&o.lang, "lang", "", i18n.T("commands.lang_flag"))
+	flags.BoolVar(&o.demo, "demo", false, i18n.T("commands.demo_flag"))
+	flags.StringVar(&o.fixture, "fixture", "", i18n.T("commands.fixture_flag"))
+	flags.BoolVar(&o.noCache, "no-cache", false, i18n.T("commands.no_cache_flag"))
+	flags.Usage = func() {
+		commands.PrintUsage(flags.Output())
+		fmt.Fprintln(flags.Output(), "\n"+i18n.T("commands.flags"))
+		flags.PrintDefaults()
+	}
+	return flags, &o
+}
+
+// fakeClients serves every AWS service from the fake account
+func fakeClients(account *fake.AWS) main_model.Clients {
+	return main_model.Clients{
+		Lambda:   account,
+		AppSync:  account,
+		S3:       account,
+		DynamoDB: account,
+		IAM:      account,
+		Caller:   account,
+	}
 }
@@ -0,0 +1,18 @@
+package messages
+
+import "github.com/xsevy/terrapi/aws"
+
+// APIsListedMsg is sent when the AppSync APIs of a region were listed in the background
+type APIsListedMsg struct {
+	Region string
+	APIs   []aws.GraphqlAPI
+	Err    error
+}
+
+// APIExportedMsg is sent when the AppSync API chosen for an import was exported in the background
+type APIExportedMsg struct {
+	Region string
+	APIID  string
+	Export *aws.APIExport
+	Err    error
+}
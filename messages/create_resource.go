@@ -1,32 +1,57 @@
 package messages
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/aws"
+)
 
 type CreateResourceMsg struct {
-	ID                       string
-	ProjectName              string
-	LambdaRuntime            string
-	AWSRegion                string
-	BackendBucket            string
-	BackendLockTable         string
-	AuthorizerLambdaFunction string
+	ID                        string
+	ProjectName               string
+	LambdaRuntime             string
+	AWSRegion                 string
+	BackendBucket             string
+	BackendLockTable          string
+	BackendKey                string
+	BackendWorkspaceKeyPrefix string
+	AuthorizerLambdaFunction  string
+	Environments              []string
+	AppSyncExport             *aws.APIExport
+	// Destination is the directory new projects are created in, or a directory of the project a resource is added to
+	Destination string
+	// GitInit creates a git repository with the new project
+	GitInit bool
+	// Force runs the operation on a project with uncommitted changes
+	Force bool
+}
+
+// DestinationDir returns Destination, or the current directory when no destination was chosen
+func (m CreateResourceMsg) DestinationDir() string {
+	if m.Destination == "" {
+		return "./"
+	}
+	return m.Destination
 }
 
 type createResourceOption func(*CreateResourceMsg)
 
 func CreateResource(id string, ProjectName string, options ...createResourceOption) tea.Cmd {
 	return func() tea.Msg {
-		msg := &CreateResourceMsg{
-			ID:          id,
-			ProjectName: ProjectName,
-		}
-		for _, opt := range options {
-			opt(msg)
-		}
-		return *msg
+		return NewCreateResourceMsg(id, ProjectName, options...)
 	}
 }
 
+func NewCreateResourceMsg(id string, ProjectName string, options ...createResourceOption) CreateResourceMsg {
+	msg := &CreateResourceMsg{
+		ID:          id,
+		ProjectName: ProjectName,
+	}
+	for _, opt := range options {
+		opt(msg)
+	}
+	return *msg
+}
+
 func WithLambdaRuntime(runtime string) createResourceOption {
 	return func(msg *CreateResourceMsg) {
 		msg.LambdaRuntime = runtime
@@ -51,8 +76,50 @@ func WithBackendLockTable(table string) createResourceOption {
 	}
 }
 
+func WithBackendKey(key string) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.BackendKey = key
+	}
+}
+
+func WithBackendWorkspaceKeyPrefix(prefix string) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.BackendWorkspaceKeyPrefix = prefix
+	}
+}
+
 func WithAuthorizerLambdaFunction(lambda string) createResourceOption {
 	return func(msg *CreateResourceMsg) {
 		msg.AuthorizerLambdaFunction = lambda
 	}
 }
+
+func WithEnvironments(environments []string) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.Environments = environments
+	}
+}
+
+func WithAppSyncExport(export *aws.APIExport) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.AppSyncExport = export
+	}
+}
+
+func WithGitInit(init bool) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.GitInit = init
+	}
+}
+
+func WithForce(force bool) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.Force = force
+	}
+}
+
+func WithDestination(dir string) createResourceOption {
+	return func(msg *CreateResourceMsg) {
+		msg.Destination = dir
+	}
+}
@@ -0,0 +1,6 @@
+package messages
+
+// EditorClosedMsg is sent when the editor opened on a project file exits
+type EditorClosedMsg struct {
+	Err error
+}
@@ -0,0 +1,9 @@
+package messages
+
+import "github.com/xsevy/terrapi/aws"
+
+// IdentityMsg is sent when STS told whose credentials the AWS requests are made with
+type IdentityMsg struct {
+	Identity aws.Identity
+	Err      error
+}
@@ -0,0 +1,16 @@
+package messages
+
+// AWSRefreshedMsg is sent when the cached AWS lookups of the setup column were listed again
+type AWSRefreshedMsg struct {
+	Err error
+}
+
+// ListsLookedUpMsg is sent when the lists of the setup form ID were looked up in the background,
+// Errs holds the errors of the failed lookups by field
+type ListsLookedUpMsg struct {
+	ID    string
+	Lists map[string][]string
+	Errs  map[string]error
+	// Reload is set for the lists looked up again after AWSRefreshedMsg, a failed lookup keeps the previous items
+	Reload bool
+}
@@ -0,0 +1,57 @@
+package messages
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ReviewField is a value chosen in the setup form, Field is the name of the form field to go back to
+type ReviewField struct {
+	Field string
+	Label string
+	Value string
+}
+
+// ReviewResourceMsg asks to confirm the creation of Resource
+type ReviewResourceMsg struct {
+	Resource CreateResourceMsg
+	Fields   []ReviewField
+	// Warnings are known by the setup form, the review adds its own
+	Warnings []string
+}
+
+// EditResourceMsg goes back from the review to the setup form, with Field selected
+type EditResourceMsg struct {
+	Field string
+}
+
+// ResourceCreatedMsg reports the result of CreateResourceMsg
+type ResourceCreatedMsg struct {
+	Resource CreateResourceMsg
+	// Dir is the directory of the project
+	Dir string
+	Err error
+}
+
+func ReviewResource(resource CreateResourceMsg, fields []ReviewField, warnings []string) tea.Cmd {
+	return func() tea.Msg {
+		return ReviewResourceMsg{
+			Resource: resource,
+			Fields:   fields,
+			Warnings: warnings,
+		}
+	}
+}
+
+func EditResource(field string) tea.Cmd {
+	return func() tea.Msg {
+		return EditResourceMsg{Field: field}
+	}
+}
+
+func ResourceCreated(resource CreateResourceMsg, dir string, err error) tea.Cmd {
+	return func() tea.Msg {
+		return ResourceCreatedMsg{
+			Resource: resource,
+			Dir:      dir,
+			Err:      err,
+		}
+	}
+}
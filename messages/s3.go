@@ -0,0 +1,8 @@
+package messages
+
+// StatesListedMsg is sent when the terraform states of a backend bucket were listed in the background
+type StatesListedMsg struct {
+	Bucket string
+	States []string
+	Err    error
+}
@@ -0,0 +1,25 @@
+package messages
+
+import tea "github.com/charmbracelet/bubbletea"
+
+type StartTerraformMsg struct {
+	Binary string
+	Dir    string
+}
+
+type TerraformOutputMsg struct {
+	Line string
+}
+
+type TerraformDoneMsg struct {
+	Err error
+}
+
+func StartTerraform(binary string, dir string) tea.Cmd {
+	return func() tea.Msg {
+		return StartTerraformMsg{
+			Binary: binary,
+			Dir:    dir,
+		}
+	}
+}
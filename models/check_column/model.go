@@ -0,0 +1,97 @@
+package check_column
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/bubbles"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/linter"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/styles"
+)
+
+type CheckColumnModel struct {
+	issues []linter.Issue
+	list   *bubbles.ListModel
+	err    error
+	keys   helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewCheckColumnModel(focused bool) *CheckColumnModel {
+	m := &CheckColumnModel{
+		keys: helpers.Keys,
+	}
+	m.SetFocused(focused)
+	return m
+}
+
+func (m *CheckColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *CheckColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.Escape) {
+			return m, messages.SwitchColumn("select_column")
+		}
+	}
+
+	if m.list != nil {
+		m.list.Update(msg)
+	}
+	return m, nil
+}
+
+func (m *CheckColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	var content string
+	switch {
+	case m.err != nil:
+		content = i18n.T("check.error", m.err)
+	case len(m.issues) == 0:
+		content = i18n.T("check.no_issues")
+	default:
+		content = m.summary() + "\n\n" + m.list.View()
+	}
+
+	return m.Render(styles.SetupColumnStyleFocused, content, 0, 0)
+}
+
+// Check lints the project in dir and lists the issues found
+func (m *CheckColumnModel) Check(dir string) {
+	m.issues, m.err = linter.Lint(os.DirFS(dir))
+
+	items := make([]string, 0, len(m.issues))
+	for _, issue := range m.issues {
+		items = append(items, fmt.Sprintf("[%s] %s:%d\n%s", issue.Severity, issue.File, issue.Line, issue.Message))
+	}
+	m.list = bubbles.NewListModel(i18n.T("check.issues"), items, false, true)
+}
+
+// summary counts the issues by severity
+func (m *CheckColumnModel) summary() string {
+	counts := map[linter.Severity]int{}
+	for _, issue := range m.issues {
+		counts[issue.Severity]++
+	}
+
+	parts := []string{}
+	for _, s := range []linter.Severity{linter.SeverityError, linter.SeverityWarning, linter.SeverityInfo} {
+		if counts[s] > 0 {
+			parts = append(parts, i18n.N("check.severity."+s.String(), counts[s]))
+		}
+	}
+
+	return i18n.N("check.summary", len(m.issues), len(m.issues), strings.Join(parts, ", "))
+}
@@ -0,0 +1,221 @@
+package dashboard_column
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/bubbles"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/overview"
+	"github.com/xsevy/terrapi/styles"
+)
+
+// item is a part of the project listed in the dashboard with the files defining it
+type item struct {
+	name  string
+	files []string
+}
+
+// DashboardColumnModel shows what a project contains. Enter on an item lists its files,
+// enter on a file opens it in $EDITOR.
+type DashboardColumnModel struct {
+	dir      string
+	overview *overview.Overview
+	items    []item
+	list     *bubbles.ListModel
+	// files lists the files of the chosen item, it is nil while the items are listed
+	files *bubbles.ListModel
+	err   error
+	keys  helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewDashboardColumnModel(focused bool) *DashboardColumnModel {
+	m := &DashboardColumnModel{
+		keys: helpers.Keys,
+	}
+	m.SetFocused(focused)
+	return m
+}
+
+func (m *DashboardColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DashboardColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			if m.files != nil {
+				m.files = nil
+				return m, nil
+			}
+			return m, messages.SwitchColumn("select_column")
+		case key.Matches(msg, m.keys.Enter):
+			switch {
+			case m.files != nil:
+				if file := m.files.Value(); file != "" {
+					return m, openEditor(filepath.Join(m.dir, filepath.FromSlash(file)))
+				}
+			case m.list != nil:
+				if item, ok := m.selectedItem(); ok {
+					m.files = bubbles.NewListModel(item.name, item.files, false, true)
+				}
+			}
+			return m, nil
+		}
+	case messages.EditorClosedMsg:
+		m.err = msg.Err
+		if m.err == nil {
+			// the edited files may define other resources, the items are read again
+			m.read()
+		}
+		return m, nil
+	}
+
+	switch {
+	case m.files != nil:
+		m.files.Update(msg)
+	case m.list != nil:
+		m.list.Update(msg)
+	}
+	return m, nil
+}
+
+func (m *DashboardColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	if m.overview == nil {
+		return m.Render(styles.SetupColumnStyleFocused, i18n.T("dashboard.error", m.err), 0, 0)
+	}
+
+	content := styles.GetFocusedTitle(i18n.T("dashboard.title", m.overview.API.Name), true) + "\n\n" + m.summary() + "\n\n"
+	if m.files != nil {
+		content += m.files.View() + "\n\n" + i18n.T("dashboard.files_help")
+	} else {
+		content += m.list.View() + "\n\n" + i18n.T("dashboard.items_help")
+	}
+	if m.err != nil {
+		content += "\n\n" + styles.FieldErrorStyle.Render(m.err.Error())
+	}
+
+	return m.Render(styles.SetupColumnStyleFocused, content, 0, 0)
+}
+
+// Show reads the project in dir and lists its API, schema, data sources and resolvers
+func (m *DashboardColumnModel) Show(dir string) {
+	m.dir = dir
+	m.files = nil
+	m.err = nil
+	m.read()
+}
+
+// read lists the items of the project, the selected item stays selected when it still exists
+func (m *DashboardColumnModel) read() {
+	m.overview, m.err = overview.Read(m.dir)
+	if m.err != nil {
+		return
+	}
+
+	o := m.overview
+	m.items = []item{
+		{name: i18n.T("dashboard.api"), files: o.API.Files},
+		{name: i18n.T("dashboard.schema"), files: o.Schema.Files},
+	}
+	for _, d := range o.DataSources {
+		name := i18n.T("dashboard.data_source", d.Name, d.Type)
+		if d.Runtime != "" {
+			name = i18n.T("dashboard.data_source", d.Name, d.Runtime)
+		}
+		m.items = append(m.items, item{name: name, files: d.Files})
+	}
+	for _, r := range o.Resolvers {
+		name := i18n.T("dashboard.resolver", r.Type, r.Field)
+		if r.DataSource != "" {
+			name += " → " + r.DataSource
+		}
+		m.items = append(m.items, item{name: name, files: r.Files})
+	}
+
+	names := make([]string, 0, len(m.items))
+	for _, item := range m.items {
+		names = append(names, item.name)
+	}
+	if m.list == nil {
+		m.list = bubbles.NewListModel(i18n.T("dashboard.contents"), names, false, true)
+	} else {
+		value := m.list.Value()
+		m.list.SetItems(names)
+		m.list.Select(value)
+	}
+}
+
+// summary describes the API and counts the resources of the project
+func (m *DashboardColumnModel) summary() string {
+	o := m.overview
+	lines := []string{
+		i18n.T("dashboard.auth_mode", valueOrUnknown(o.API.AuthMode)),
+		i18n.T("dashboard.region", valueOrUnknown(o.API.Region)),
+		i18n.T("dashboard.backend", valueOrUnknown(o.API.Backend)),
+	}
+	if len(o.Environments) > 0 {
+		lines = append(lines, i18n.T("dashboard.environments", strings.Join(o.Environments, ", ")))
+	}
+	lines = append(lines,
+		i18n.T("dashboard.schema_stats", strings.Join([]string{
+			i18n.N("dashboard.types", o.Schema.Types),
+			i18n.N("dashboard.queries", o.Schema.Queries),
+			i18n.N("dashboard.mutations", o.Schema.Mutations),
+			i18n.N("dashboard.subscriptions", o.Schema.Subscriptions),
+		}, ", ")),
+		i18n.N("dashboard.data_sources", len(o.DataSources))+", "+i18n.N("dashboard.resolvers", len(o.Resolvers)),
+	)
+	return strings.Join(lines, "\n")
+}
+
+func (m *DashboardColumnModel) selectedItem() (item, bool) {
+	value := m.list.Value()
+	for _, item := range m.items {
+		if item.name == value {
+			return item, len(item.files) > 0
+		}
+	}
+	return item{}, false
+}
+
+// openEditor suspends the program while $VISUAL or $EDITOR edits path
+func openEditor(path string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return func() tea.Msg {
+			return messages.EditorClosedMsg{Err: errors.New(i18n.T("dashboard.no_editor"))}
+		}
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return messages.EditorClosedMsg{Err: err}
+	})
+}
+
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return i18n.T("dashboard.unknown")
+	}
+	return value
+}
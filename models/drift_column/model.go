@@ -0,0 +1,248 @@
+package drift_column
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/drift"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/styles"
+)
+
+const (
+	// tableMargin is the height of the summary and the detail around the table
+	tableMargin = 10
+	// environmentsHeight is the height of the environment selector above the summary
+	environmentsHeight = 2
+	// tableFixedWidth is the width of the kind and status columns and the cell padding,
+	// the name column takes the rest
+	tableFixedWidth = 30
+)
+
+// DriftDetectedMsg is sent when the project in Dir was compared with AWS in the background.
+// It is declared here and not in messages, which the tests of drift import.
+type DriftDetectedMsg struct {
+	Dir         string
+	Environment string
+	Report      *drift.Report
+	Err         error
+}
+
+type DriftColumnModel struct {
+	// ctx cancels the AWS requests of the detection
+	ctx           context.Context
+	appsyncClient aws.AppSync
+	lambdaClient  aws.Lambda
+	iamClient     aws.IAM
+	// dir is the compared project
+	dir string
+	// environments are the environments of the project, the selected one is compared
+	environments []string
+	environment  int
+	detecting    bool
+	report       *drift.Report
+	table        table.Model
+	err          error
+	keys         helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewDriftColumnModel(ctx context.Context, appsyncClient aws.AppSync, lambdaClient aws.Lambda, iamClient aws.IAM, focused bool) *DriftColumnModel {
+	tableStyles := table.DefaultStyles()
+	tableStyles.Header = tableStyles.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color(helpers.Colors.Muted)).
+		BorderBottom(true)
+	tableStyles.Selected = styles.SelectedChoiceStyle.Copy().Bold(true)
+
+	m := &DriftColumnModel{
+		ctx:           ctx,
+		appsyncClient: appsyncClient,
+		lambdaClient:  lambdaClient,
+		iamClient:     iamClient,
+		table: table.New(
+			table.WithFocused(true),
+			table.WithStyles(tableStyles),
+		),
+		keys: helpers.Keys,
+	}
+	m.SetFocused(focused)
+	m.SetSize(styles.DefaultLayout.RightWidth, styles.DefaultLayout.RightHeight)
+	return m
+}
+
+// SetSize fits the table in the column
+func (m *DriftColumnModel) SetSize(width, height int) {
+	m.ColumnModel.SetSize(width, height)
+	m.table.SetColumns([]table.Column{
+		{Title: i18n.T("drift.kind"), Width: 9},
+		{Title: i18n.T("drift.name"), Width: max(width-tableFixedWidth, 10)},
+		{Title: i18n.T("drift.status"), Width: 10},
+	})
+	m.table.SetHeight(max(height-m.margin(), 3))
+}
+
+// margin is the height of the column without the table
+func (m *DriftColumnModel) margin() int {
+	if len(m.environments) > 0 {
+		return tableMargin + environmentsHeight
+	}
+	return tableMargin
+}
+
+func (m *DriftColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DriftColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			return m, messages.SwitchColumn("select_column")
+		case key.Matches(msg, m.keys.Tab) && len(m.environments) > 1:
+			m.environment = (m.environment + 1) % len(m.environments)
+			return m, m.detect()
+		case key.Matches(msg, m.keys.ShiftTab) && len(m.environments) > 1:
+			m.environment = (m.environment + len(m.environments) - 1) % len(m.environments)
+			return m, m.detect()
+		}
+	case DriftDetectedMsg:
+		// the result of an environment or a project which is not shown anymore is dropped
+		if msg.Dir == m.dir && msg.Environment == m.selectedEnvironment() {
+			m.setReport(msg.Report, msg.Err)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m *DriftColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	content := m.environmentsView()
+	switch {
+	case m.detecting:
+		content += i18n.T("drift.detecting")
+	case m.err != nil:
+		content += i18n.T("drift.error", m.err)
+	case len(m.report.Differences) == 0:
+		content += m.summary() + "\n\n" + i18n.T("drift.no_drift")
+	default:
+		content += m.summary() + "\n\n" + m.table.View() + "\n\n" + m.detail()
+	}
+
+	return m.Render(styles.SetupColumnStyleFocused, content, 0, 0)
+}
+
+// Detect compares the project in dir with AWS in the background, projects using environments are
+// compared in the environment selected last, or in their first one
+func (m *DriftColumnModel) Detect(dir string) tea.Cmd {
+	previous := m.selectedEnvironment()
+	m.dir, m.environments, m.environment = dir, nil, 0
+	m.report, m.err, m.detecting = nil, nil, false
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		m.err = err
+		m.SetSize(m.GetWidth(), m.GetHeight())
+		return nil
+	}
+	if manifest.HasEnvironments() {
+		m.environments = manifest.Environments
+		m.environment = max(slices.Index(m.environments, previous), 0)
+	}
+	m.SetSize(m.GetWidth(), m.GetHeight())
+	return m.detect()
+}
+
+// detect compares the project with the selected environment, the result is sent as a DriftDetectedMsg
+func (m *DriftColumnModel) detect() tea.Cmd {
+	m.detecting = true
+	ctx, dir, env := m.ctx, m.dir, m.selectedEnvironment()
+	appsyncClient, lambdaClient, iamClient := m.appsyncClient, m.lambdaClient, m.iamClient
+	return func() tea.Msg {
+		report, err := drift.Detect(ctx, dir, env, appsyncClient, lambdaClient, iamClient)
+		return DriftDetectedMsg{Dir: dir, Environment: env, Report: report, Err: err}
+	}
+}
+
+// selectedEnvironment is the compared environment, empty for projects without environments
+func (m *DriftColumnModel) selectedEnvironment() string {
+	if m.environment >= len(m.environments) {
+		return ""
+	}
+	return m.environments[m.environment]
+}
+
+// setReport shows the differences of report, or err when the detection failed
+func (m *DriftColumnModel) setReport(report *drift.Report, err error) {
+	m.detecting = false
+	m.report, m.err = report, err
+	if err != nil {
+		return
+	}
+
+	rows := make([]table.Row, 0, len(report.Differences))
+	for _, d := range report.Differences {
+		rows = append(rows, table.Row{string(d.Kind), d.Name, string(d.Status)})
+	}
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+}
+
+// environmentsView shows the environments of the project with the compared one selected
+func (m *DriftColumnModel) environmentsView() string {
+	if len(m.environments) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(m.environments))
+	for i, env := range m.environments {
+		if i == m.environment {
+			names = append(names, styles.SelectedChoiceStyle.Render(env))
+		} else {
+			names = append(names, env)
+		}
+	}
+	return i18n.T("drift.environment", strings.Join(names, " · ")) + "\n\n"
+}
+
+// summary names the compared project and counts the differences
+func (m *DriftColumnModel) summary() string {
+	s := i18n.T("drift.project", m.report.Project, m.report.Region)
+	if m.report.Environment != "" {
+		s += fmt.Sprintf(" (%s)", m.report.Environment)
+	}
+	return s + "\n" + i18n.N("drift.differences", len(m.report.Differences))
+}
+
+// detail shows the full name and the detail of the selected difference
+func (m *DriftColumnModel) detail() string {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.report.Differences) {
+		return ""
+	}
+
+	d := m.report.Differences[cursor]
+	if d.Detail == "" {
+		return d.Name
+	}
+	return d.Name + "\n" + d.Detail
+}
@@ -0,0 +1,100 @@
+package main_model
+
+import (
+	"context"
+	"time"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/aws/cache"
+	"github.com/xsevy/terrapi/models/check_column"
+	"github.com/xsevy/terrapi/models/dashboard_column"
+	"github.com/xsevy/terrapi/models/drift_column"
+	"github.com/xsevy/terrapi/models/menu"
+	"github.com/xsevy/terrapi/models/result_column"
+	"github.com/xsevy/terrapi/models/review_column"
+	"github.com/xsevy/terrapi/models/select_column"
+	"github.com/xsevy/terrapi/models/select_column_choices"
+	"github.com/xsevy/terrapi/models/setup_column"
+	"github.com/xsevy/terrapi/models/terraform_column"
+)
+
+// Clients are the AWS services the columns list resources from
+type Clients struct {
+	Lambda   aws.Lambda
+	AppSync  aws.AppSync
+	S3       aws.S3
+	DynamoDB aws.DynamoDB
+	IAM      aws.IAM
+	// Caller tells whose credentials the requests are made with, nil shows no header
+	Caller aws.Caller
+	// Cache holds the lists of the forms, nil when they are not cached
+	Cache *cache.Cache
+}
+
+// NewClients returns the clients of the AWS account of the shared configuration, the options select
+// the profile, the region, the retries and the timeouts. The lists of the forms are kept in cacheDir
+// for ttl, an empty cacheDir disables the cache.
+func NewClients(ctx context.Context, cacheDir string, ttl time.Duration, opts ...aws.Option) (Clients, error) {
+	awsClient, err := aws.NewAWS(ctx, opts...)
+	if err != nil {
+		return Clients{}, err
+	}
+	clients := Clients{
+		Lambda:   aws.NewLambda(awsClient),
+		AppSync:  aws.NewAppSync(awsClient),
+		S3:       aws.NewS3(awsClient),
+		DynamoDB: aws.NewDynamoDB(awsClient),
+		IAM:      aws.NewIAM(awsClient),
+		Caller:   awsClient,
+	}
+	if cacheDir == "" {
+		return clients, nil
+	}
+
+	scope := cache.Scope{Profile: awsClient.Profile(), Region: awsClient.Region()}
+	// without STS the account is unknown, the lists of the profile are still served
+	if identity, err := awsClient.Identity(ctx); err == nil {
+		scope.Account = identity.Account
+	}
+	c := cache.Open(cacheDir, scope, ttl)
+	clients.Cache = c
+	clients.Lambda = c.Lambda(clients.Lambda)
+	clients.AppSync = c.AppSync(clients.AppSync)
+	clients.S3 = c.S3(clients.S3)
+	clients.DynamoDB = c.DynamoDB(clients.DynamoDB)
+	return clients, nil
+}
+
+// NewApp creates the columns of the interactive mode and the main model holding them.
+// The keys, styles and locale are copied, they have to be set before. Cancelling ctx cancels the AWS requests.
+// Resources are created in the accounts of productionAccounts after a confirmation.
+func NewApp(ctx context.Context, clients Clients, productionAccounts []string) *mainModel {
+	selectColumnChoices := select_column_choices.NewSelectColumnChoicesModel()
+	selectColumn := select_column.NewSelectColumnModel(selectColumnChoices, true)
+	setupColumn := setup_column.NewSetupColumnModel(
+		ctx,
+		clients.Lambda,
+		clients.AppSync,
+		clients.S3,
+		clients.DynamoDB,
+		clients.Cache,
+		false,
+	)
+	checkColumn := check_column.NewCheckColumnModel(false)
+	driftColumn := drift_column.NewDriftColumnModel(ctx, clients.AppSync, clients.Lambda, clients.IAM, false)
+	terraformColumn := terraform_column.NewTerraformColumnModel(false)
+	reviewColumn := review_column.NewReviewColumnModel(false)
+	resultColumn := result_column.NewResultColumnModel(false)
+	dashboardColumn := dashboard_column.NewDashboardColumnModel(false)
+
+	return NewMainModel(ctx, menu.NewMenuModel(
+		selectColumn,
+		setupColumn,
+		checkColumn,
+		driftColumn,
+		terraformColumn,
+		reviewColumn,
+		resultColumn,
+		dashboardColumn,
+	), clients.Caller, productionAccounts)
+}
@@ -0,0 +1,114 @@
+package main_model
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/styles"
+)
+
+// header shows the account, the caller, the profile and the region of the AWS requests, so resources are not
+// created with the wrong credentials. Resources are only created in a production account after a confirmation.
+type header struct {
+	ctx    context.Context
+	caller aws.Caller
+	// production are the IDs of the production accounts
+	production []string
+
+	identity *aws.Identity
+	err      error
+	// pending is the resource waiting for the confirmation
+	pending *messages.CreateResourceMsg
+}
+
+// loadIdentity asks STS who the caller is, the header shows the profile and the region meanwhile
+func (h *header) loadIdentity() tea.Cmd {
+	if h.caller == nil {
+		return nil
+	}
+
+	ctx, caller := h.ctx, h.caller
+	return func() tea.Msg {
+		identity, err := caller.Identity(ctx)
+		return messages.IdentityMsg{Identity: identity, Err: err}
+	}
+}
+
+func (h *header) setIdentity(msg messages.IdentityMsg) {
+	if msg.Err != nil {
+		h.identity, h.err = nil, msg.Err
+		return
+	}
+	identity := msg.Identity
+	h.identity, h.err = &identity, nil
+}
+
+// isProduction reports whether the account is a production account
+func (h *header) isProduction() bool {
+	return h.identity != nil && slices.Contains(h.production, h.identity.Account)
+}
+
+// guard holds msg until it is confirmed when the account is a production account, or could not be
+// checked while production accounts are configured. It reports whether msg is held.
+func (h *header) guard(msg messages.CreateResourceMsg) bool {
+	if len(h.production) == 0 || (h.identity != nil && !h.isProduction()) {
+		return false
+	}
+	h.pending = &msg
+	return true
+}
+
+// confirm returns the held resource and releases it
+func (h *header) confirm() *messages.CreateResourceMsg {
+	msg := h.pending
+	h.pending = nil
+	return msg
+}
+
+func (h *header) View(width int) string {
+	if h.caller == nil {
+		return ""
+	}
+
+	var account string
+	switch {
+	case h.identity != nil:
+		account = i18n.T("header.account", h.identity.Account) + " · " + h.identity.ARN
+	case h.err != nil:
+		account = i18n.T("header.unknown_account", h.err)
+	default:
+		account = i18n.T("header.loading")
+	}
+	parts := []string{account, i18n.T("header.profile", h.caller.Profile()), h.caller.Region()}
+
+	style := styles.DisabledChoiceStyle
+	if h.isProduction() {
+		parts = append([]string{i18n.T("header.production")}, parts...)
+		style = styles.FieldErrorStyle.Copy().Bold(true)
+	} else if h.err != nil {
+		style = styles.FieldErrorStyle
+	}
+	if width > 0 {
+		style = style.Copy().MaxWidth(width)
+	}
+	lines := []string{style.Render(strings.Join(parts, " · "))}
+
+	if h.pending != nil {
+		prompt := i18n.T("header.confirm_unknown", h.pending.ProjectName)
+		if h.identity != nil {
+			prompt = i18n.T("header.confirm_production", h.pending.ProjectName, h.identity.Account)
+		}
+		promptStyle := styles.FieldErrorStyle
+		if width > 0 {
+			promptStyle = promptStyle.Copy().Width(width)
+		}
+		lines = append(lines, promptStyle.Render(prompt))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,32 +1,44 @@
 package main_model
 
 import (
+	"context"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xsevy/terrapi/aws"
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/models/drift_column"
 	"github.com/xsevy/terrapi/models/menu"
+	"github.com/xsevy/terrapi/styles"
 	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/vcs"
 )
 
 type mainModel struct {
-	menu *menu.MenuModel
-	help help.Model
-	keys helpers.KeyMap
+	menu   *menu.MenuModel
+	header *header
+	help   help.Model
+	keys   helpers.KeyMap
+	width  int
+	height int
 }
 
-func NewMainModel(menu *menu.MenuModel) *mainModel {
+// NewMainModel shows menu under a header telling whose credentials the requests of caller are made with,
+// a nil caller shows no header. Resources are created in the production accounts after a confirmation.
+func NewMainModel(ctx context.Context, menu *menu.MenuModel, caller aws.Caller, productionAccounts []string) *mainModel {
 	return &mainModel{
-		keys: helpers.Keys,
-		menu: menu,
+		keys:   helpers.Keys,
+		menu:   menu,
+		header: &header{ctx: ctx, caller: caller, production: productionAccounts},
 	}
 }
 
 func (m *mainModel) Init() tea.Cmd {
-	return nil
+	return m.header.loadIdentity()
 }
 
 func (m *mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -36,11 +48,26 @@ func (m *mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.help.Width = msg.Width
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
 
 	case tea.KeyMsg:
 		switch {
+		case m.header.pending != nil:
+			// the creation in a production account is confirmed or cancelled before anything else
+			switch {
+			case key.Matches(msg, m.keys.Quit):
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Enter):
+				cmd = m.create(*m.header.confirm())
+				m.resize()
+			case key.Matches(msg, m.keys.Escape):
+				m.header.confirm()
+				m.resize()
+			}
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
+			m.resize()
 		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 		default:
@@ -54,22 +81,74 @@ func (m *mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.CloseSetupMsg:
 		newMenu, cmd = m.menu.Update(msg)
 		m.menu = newMenu.(*menu.MenuModel)
+	case messages.ReviewResourceMsg, messages.EditResourceMsg, messages.ResourceCreatedMsg:
+		newMenu, cmd = m.menu.Update(msg)
+		m.menu = newMenu.(*menu.MenuModel)
+	case messages.StartTerraformMsg, messages.TerraformOutputMsg, messages.TerraformDoneMsg, messages.EditorClosedMsg:
+		newMenu, cmd = m.menu.Update(msg)
+		m.menu = newMenu.(*menu.MenuModel)
+	case messages.AWSRefreshedMsg, messages.ListsLookedUpMsg, messages.APIsListedMsg, messages.APIExportedMsg, messages.StatesListedMsg,
+		drift_column.DriftDetectedMsg:
+		newMenu, cmd = m.menu.Update(msg)
+		m.menu = newMenu.(*menu.MenuModel)
+	case messages.IdentityMsg:
+		m.header.setIdentity(msg)
+		m.resize()
 	case messages.CreateResourceMsg:
-		err := templates.CreateResources(msg.ID, "./", &msg)
-		if err != nil {
-			panic(err)
+		if m.header.guard(msg) {
+			m.resize()
+			return m, nil
 		}
-		return m, tea.Quit
+		return m, m.create(msg)
 	}
 
 	return m, cmd
 }
 
+// create writes the files of the resource, the result column shows the outcome
+func (m *mainModel) create(msg messages.CreateResourceMsg) tea.Cmd {
+	dest := msg.DestinationDir()
+	dir := templates.ProjectDir(msg.ID, dest, &msg)
+	create := func() error {
+		return templates.CreateResources(msg.ID, dest, &msg)
+	}
+
+	var err error
+	switch msg.ID {
+	case helpers.ResourceIDs.CreateAppSyncAPI, helpers.ResourceIDs.ImportAppSyncAPI:
+		// new projects have no repository yet, it is created with the first commit
+		err = create()
+		if err == nil && msg.GitInit {
+			err = vcs.Init(dir, templates.Summary(msg.ID, &msg))
+		}
+	default:
+		err = vcs.Run(dir, msg.Force, templates.Summary(msg.ID, &msg), create)
+	}
+
+	return messages.ResourceCreated(msg, dir, err)
+}
+
 func (m *mainModel) View() string {
 	menuView := m.menu.View()
+	if headerView := m.header.View(m.width); headerView != "" {
+		menuView = headerView + "\n" + menuView
+	}
 	helpView := m.help.View(m.keys)
 
-	height := 8 - strings.Count(helpView, "\n")
+	// the help stays at the bottom of the terminal
+	padding := max(m.height-lipgloss.Height(menuView)-lipgloss.Height(helpView), 0)
 
-	return menuView + strings.Repeat("\n", height) + helpView
+	return menuView + strings.Repeat("\n", padding+1) + helpView
+}
+
+// resize fits the columns in the terminal between the header and the help
+func (m *mainModel) resize() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+	height := m.height - lipgloss.Height(m.help.View(m.keys))
+	if headerView := m.header.View(m.width); headerView != "" {
+		height -= lipgloss.Height(headerView)
+	}
+	m.menu.SetLayout(styles.NewLayout(m.width, height))
 }
@@ -0,0 +1,787 @@
+package main_model
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/aws/cache"
+	"github.com/xsevy/terrapi/aws/fake"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/vcs"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the rendered frames")
+
+// cmdTimeout is how long a command may take before it is considered a timer, like the cursor blink, and dropped
+const cmdTimeout = 50 * time.Millisecond
+
+// fixture is the account the flows run against
+var fixture = fake.Fixture{
+	Caller: fake.Caller{
+		Account: "123456789012",
+		ARN:     "arn:aws:sts::123456789012:assumed-role/developer/alice",
+		Profile: "dev",
+		Region:  "eu-central-1",
+	},
+	Regions:   []string{"eu-central-1", "us-east-1"},
+	Runtimes:  []string{"python3.11"},
+	Functions: []string{"authorizer", "users_handler"},
+	Buckets:   map[string][]string{"terraform-states": {"blog/terraform.tfstate"}},
+	Tables:    []string{"terraform-locks"},
+}
+
+// driver runs the program without a terminal: it sends messages, runs the returned commands and renders frames
+type driver struct {
+	t     *testing.T
+	model tea.Model
+	// dir is the working directory of the program, it replaces the temporary path in the frames
+	dir string
+}
+
+// newDriver starts the program against account, the resources of the production accounts need a confirmation
+func newDriver(t *testing.T, account *fake.AWS, productionAccounts ...string) *driver {
+	t.Helper()
+	clients := Clients{Lambda: account, AppSync: account, S3: account, DynamoDB: account, IAM: account, Caller: account}
+	return newClientsDriver(t, clients, productionAccounts...)
+}
+
+// newClientsDriver starts the program against clients
+func newClientsDriver(t *testing.T, clients Clients, productionAccounts ...string) *driver {
+	t.Helper()
+
+	// terraform is not found, the flow does not depend on the binaries installed
+	t.Setenv("PATH", "")
+	i18n.SetLocale(i18n.DefaultLocale)
+	helpers.Keys = helpers.DefaultKeyMap()
+	styles.SetTheme(helpers.Themes[helpers.DefaultTheme])
+	lipgloss.SetColorProfile(termenv.Ascii)
+
+	dir := filepath.Join(t.TempDir(), "workspace")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	d := &driver{
+		t:     t,
+		model: NewApp(context.Background(), clients, productionAccounts),
+		dir:   dir,
+	}
+	d.send(run(d.model.Init())...)
+	// the terminal is wide enough for the temporary paths not to wrap
+	d.send(tea.WindowSizeMsg{Width: 200, Height: 40})
+	return d
+}
+
+// send updates the model with the messages and with the messages of the commands they return
+func (d *driver) send(msgs ...tea.Msg) {
+	queue := msgs
+	for len(queue) > 0 {
+		msg := queue[0]
+		queue = queue[1:]
+
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, cmd := range batch {
+				queue = append(queue, run(cmd)...)
+			}
+			continue
+		}
+		if _, ok := msg.(tea.QuitMsg); ok {
+			continue
+		}
+
+		var cmd tea.Cmd
+		d.model, cmd = d.model.Update(msg)
+		queue = append(queue, run(cmd)...)
+	}
+}
+
+// press sends the named keys, like "enter" or "tab"
+func (d *driver) press(keys ...string) {
+	for _, k := range keys {
+		msg, ok := namedKeys[k]
+		if !ok {
+			d.t.Fatalf("unknown key %s", k)
+		}
+		d.send(msg)
+	}
+}
+
+// typeText sends the runes of text one at a time
+func (d *driver) typeText(text string) {
+	for _, r := range text {
+		d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// frame renders the model, the temporary directory is replaced so the frames do not change between runs
+func (d *driver) frame() string {
+	lines := strings.Split(d.model.View(), "\n")
+	for i, line := range lines {
+		replaced := strings.ReplaceAll(line, d.dir, "<dir>")
+		// the border closing the line stays in place
+		if padding := lipgloss.Width(line) - lipgloss.Width(replaced); padding > 0 && strings.HasSuffix(replaced, "│") {
+			replaced = strings.TrimSuffix(replaced, "│") + strings.Repeat(" ", padding) + "│"
+		}
+		lines[i] = strings.TrimRight(replaced, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// golden compares the current frame with testdata/<name>.golden, -update rewrites the file
+func (d *driver) golden(name string) {
+	d.t.Helper()
+
+	path := filepath.Join(testdataDir, name+".golden")
+	frame := d.frame()
+	if *update {
+		if err := os.WriteFile(path, []byte(frame), 0644); err != nil {
+			d.t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		d.t.Fatalf("unexpected error: %v", err)
+	}
+	if frame != string(want) {
+		d.t.Errorf("frame %s differs from %s, run the tests with -update after checking it:\n%s", name, path, frame)
+	}
+}
+
+// testdataDir is absolute, the tests change the working directory
+var testdataDir = func() string {
+	dir, err := filepath.Abs("testdata")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}()
+
+var namedKeys = map[string]tea.KeyMsg{
+	"enter":     {Type: tea.KeyEnter},
+	"esc":       {Type: tea.KeyEsc},
+	"tab":       {Type: tea.KeyTab},
+	"shift+tab": {Type: tea.KeyShiftTab},
+	"up":        {Type: tea.KeyUp},
+	"down":      {Type: tea.KeyDown},
+	"backspace": {Type: tea.KeyBackspace},
+	"ctrl+r":    {Type: tea.KeyCtrlR},
+}
+
+// run returns the message of cmd, commands which do not return quickly are timers and are dropped
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	result := make(chan tea.Msg, 1)
+	go func() { result <- cmd() }()
+
+	select {
+	case msg := <-result:
+		if msg == nil {
+			return nil
+		}
+		return []tea.Msg{msg}
+	case <-time.After(cmdTimeout):
+		return nil
+	}
+}
+
+func assertFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("expected %s to be generated: %v", name, err)
+		}
+	}
+}
+
+func TestCreateAPIAndDataSource(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+	d.golden("menu")
+
+	d.press("enter", "down", "enter")
+	d.golden("create_api_form")
+
+	d.typeText("blog")
+	// destination, region, backend bucket, state key, existing states, workspace key prefix, state lock,
+	// authorizer, environments
+	d.press("tab", "tab", "tab", "tab", "tab", "tab", "tab", "tab", "tab")
+	d.typeText("dev,prod")
+	// git repository, submit
+	d.press("tab", "down", "tab")
+	d.golden("create_api_submit")
+
+	d.press("enter")
+	d.golden("create_api_review")
+
+	for i := 0; i < 20; i++ {
+		d.press("down")
+	}
+	d.press("enter")
+	d.golden("create_api_result")
+
+	projectDir := filepath.Join(d.dir, "blog")
+	assertFiles(t, projectDir, "main.tf", "schema.graphql", project.ManifestFileName,
+		"environments/dev.tfvars", "environments/prod.tfvars")
+	if _, err := os.Stat(filepath.Join(projectDir, ".git")); !os.IsNotExist(err) {
+		t.Errorf("expected no repository when git is declined, got %v", err)
+	}
+
+	d.press("esc")
+	d.golden("menu_with_project")
+
+	d.press("up", "enter")
+	d.typeText("posts")
+	d.press("tab", "tab", "tab")
+	d.press("enter")
+	d.golden("create_data_source_review")
+
+	for i := 0; i < 10; i++ {
+		d.press("down")
+	}
+	d.press("enter")
+	d.golden("create_data_source_result")
+
+	assertFiles(t, projectDir, "posts/lambda.tf")
+}
+
+func TestFormErrors(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+
+	// the name of the API is used in terraform identifiers, it can not start with a digit
+	d.press("enter", "down", "enter")
+	d.typeText("1blog")
+	for i := 0; i < 20; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	// back to the top of the form, the errors of the skipped fields are shown
+	for i := 0; i < 20; i++ {
+		d.press("shift+tab")
+	}
+	d.golden("create_api_errors")
+
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) > 0 {
+		t.Errorf("expected nothing to be created from an invalid form, got %d entries", len(entries))
+	}
+}
+
+func TestStateKeyErrors(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+
+	// the state key is written to backend.tf, a quote would end its string
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	// destination, region, backend bucket, state key
+	d.press("tab", "tab", "tab", "tab")
+	d.typeText(`blog"/terraform.tfstate`)
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("validation.s3_key_characters")) {
+		t.Errorf("expected the state key to be rejected:\n%s", frame)
+	}
+
+	// existing states, workspace key prefix
+	d.press("tab", "tab")
+	d.typeText("/env")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("validation.s3_key_leading_slash")) {
+		t.Errorf("expected the workspace key prefix to be rejected:\n%s", frame)
+	}
+
+	// the submit button stays disabled
+	for i := 0; i < 20; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	if frame := d.frame(); strings.Contains(frame, i18n.T("review.files")) {
+		t.Errorf("expected no review of an invalid form:\n%s", frame)
+	}
+}
+
+func TestImportDemoAPI(t *testing.T) {
+	d := newDriver(t, fake.Demo())
+
+	// the apis of the first region are listed
+	d.press("enter", "down", "down", "enter", "tab")
+	d.golden("import_api_form")
+
+	// name, destination, backend bucket, state key, existing states, state lock, git repository, submit
+	for i := 0; i < 8; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	for i := 0; i < 20; i++ {
+		d.press("down")
+	}
+	d.press("enter")
+	d.golden("import_api_result")
+
+	assertFiles(t, filepath.Join(d.dir, "blog"), "imports.tf", "schema.graphql", "resolvers/Query.posts.js")
+}
+
+func TestProductionAccount(t *testing.T) {
+	d := newDriver(t, fake.New(fixture), "123456789012")
+	d.golden("production_menu")
+
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	for i := 0; i < 11; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	for i := 0; i < 20; i++ {
+		d.press("down")
+	}
+	d.press("enter")
+	d.golden("production_confirm")
+
+	// esc cancels the creation, the review stays open
+	d.press("esc")
+	if _, err := os.Stat(filepath.Join(d.dir, "blog")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing to be created before the confirmation, got %v", err)
+	}
+
+	d.press("enter", "enter")
+	assertFiles(t, filepath.Join(d.dir, "blog"), "main.tf")
+}
+
+func TestUnknownAccount(t *testing.T) {
+	account := fixture
+	account.Caller = fake.Caller{Profile: "dev", Region: "eu-central-1"}
+	d := newDriver(t, fake.New(account), "123456789012")
+	d.golden("unknown_account_menu")
+
+	// the account may be a production account, the creation is confirmed
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	for i := 0; i < 11; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	for i := 0; i < 20; i++ {
+		d.press("down")
+	}
+	d.press("enter")
+	if _, err := os.Stat(filepath.Join(d.dir, "blog")); !os.IsNotExist(err) {
+		t.Fatalf("expected nothing to be created before the confirmation, got %v", err)
+	}
+	d.press("enter")
+	assertFiles(t, filepath.Join(d.dir, "blog"), "main.tf")
+}
+
+// cachedClients serves the lists of the forms of account from c
+func cachedClients(account *fake.AWS, c *cache.Cache) Clients {
+	return Clients{
+		Lambda:   c.Lambda(account),
+		AppSync:  c.AppSync(account),
+		S3:       c.S3(account),
+		DynamoDB: c.DynamoDB(account),
+		IAM:      account,
+		Caller:   account,
+		Cache:    c,
+	}
+}
+
+func TestRefreshCachedLists(t *testing.T) {
+	dir := t.TempDir()
+	scope := cache.Scope{Account: "123456789012", Profile: "dev", Region: "eu-central-1"}
+
+	// the first session stores the lists of the form
+	d := newClientsDriver(t, cachedClients(fake.New(fixture), cache.Open(dir, scope, time.Hour)))
+	d.press("enter", "down", "enter")
+
+	// the next session serves them, although a bucket was created since
+	account := fixture
+	account.Buckets = map[string][]string{"terraform-states": {"blog/terraform.tfstate"}, "artifacts": {}}
+	d = newClientsDriver(t, cachedClients(fake.New(account), cache.Open(dir, scope, time.Hour)))
+	d.press("enter", "down", "enter")
+	if strings.Contains(d.frame(), "artifacts") {
+		t.Fatal("expected the buckets of the cache")
+	}
+
+	d.press("ctrl+r")
+	d.golden("refresh_cached_lists")
+	if frame := d.frame(); strings.Contains(frame, i18n.T("setup.refreshing")) || !strings.Contains(frame, "artifacts") {
+		t.Errorf("expected the lists to be reloaded and the status to clear:\n%s", frame)
+	}
+
+	// the refresh can be started again
+	d.press("ctrl+r")
+	if strings.Contains(d.frame(), i18n.T("setup.refreshing")) {
+		t.Error("expected the second refresh to finish")
+	}
+}
+
+// deniedExport lists the APIs of the account but is not allowed to export them
+type deniedExport struct {
+	*fake.AWS
+}
+
+func (d deniedExport) ExportAPI(ctx context.Context, region string, apiID string) (*aws.APIExport, error) {
+	return nil, errors.New("AccessDeniedException: appsync:GetIntrospectionSchema")
+}
+
+func TestImportExportError(t *testing.T) {
+	account := fake.Demo()
+	clients := Clients{Lambda: account, AppSync: deniedExport{account}, S3: account, DynamoDB: account, IAM: account, Caller: account}
+	d := newClientsDriver(t, clients)
+
+	d.press("enter", "down", "down", "enter", "tab")
+	for i := 0; i < 8; i++ {
+		d.press("tab")
+	}
+	d.press("enter")
+	// the error is shown under the API, the form stays open to choose another one
+	for i := 0; i < 20; i++ {
+		d.press("shift+tab")
+	}
+	d.golden("import_api_export_error")
+}
+
+// slowBuckets times out listing the buckets
+type slowBuckets struct {
+	*fake.AWS
+}
+
+func (s slowBuckets) ListBuckets(ctx context.Context) ([]string, error) {
+	return nil, context.DeadlineExceeded
+}
+
+func TestLookupError(t *testing.T) {
+	account := fake.New(fixture)
+	clients := Clients{Lambda: account, AppSync: account, S3: slowBuckets{account}, DynamoDB: account, IAM: account, Caller: account}
+	d := newClientsDriver(t, clients)
+
+	// the form is shown with the error of the lookup in the title of its list
+	d.press("enter", "down", "enter")
+	if frame := d.frame(); !strings.Contains(frame, "Backend bucket: unable to list (context deadline exceeded)") {
+		t.Errorf("expected the error of the buckets:\n%s", frame)
+	}
+}
+
+// createAPI creates the API name in the working directory, it becomes the current project
+func (d *driver) createAPI(name string) {
+	d.t.Helper()
+	d.send(messages.NewCreateResourceMsg(
+		helpers.ResourceIDs.CreateAppSyncAPI,
+		name,
+		messages.WithAWSRegion("eu-central-1"),
+		messages.WithBackendBucket("terraform-states"),
+		messages.WithBackendKey(name+"/terraform.tfstate"),
+		messages.WithBackendLockTable("terraform-locks"),
+		messages.WithDestination(d.dir),
+	))
+	// back to the root of the menu
+	d.press("esc", "esc")
+}
+
+func TestCheckFilter(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+	d.createAPI("blog")
+	issues := `
+resource "aws_lambda_function" "users" {}
+resource "aws_lambda_function" "posts" {}
+resource "aws_appsync_graphql_api" "legacy" {
+  xray_enabled = false
+}
+`
+	if err := os.WriteFile(filepath.Join(d.dir, "blog", "legacy.tf"), []byte(issues), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d.press("down", "down", "enter", "down", "enter")
+	frame := d.frame()
+	d.typeText("xray")
+	d.golden("check_filter")
+
+	// j and k are typed into a started filter, backspace clears it and then ends it
+	d.typeText("jk")
+	if filter := i18n.T("list.filter", "xrayjk"); !strings.Contains(d.frame(), filter) {
+		t.Errorf("expected %s:\n%s", filter, d.frame())
+	}
+	for i := 0; i < 7; i++ {
+		d.press("backspace")
+	}
+	if d.frame() != frame {
+		t.Errorf("expected the issues without filter:\n%s", d.frame())
+	}
+}
+
+// slowStates lists the states of a bucket once released
+type slowStates struct {
+	*fake.AWS
+	release chan struct{}
+}
+
+func (s slowStates) ListStates(ctx context.Context, bucket string) ([]string, error) {
+	<-s.release
+	return s.AWS.ListStates(ctx, bucket)
+}
+
+func TestSlowStates(t *testing.T) {
+	account := fake.New(fixture)
+	states := slowStates{AWS: account, release: make(chan struct{})}
+	t.Cleanup(func() { close(states.release) })
+	clients := Clients{Lambda: account, AppSync: account, S3: states, DynamoDB: account, IAM: account, Caller: account}
+	d := newClientsDriver(t, clients)
+
+	// the form is usable while the states are listed
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	frame := d.frame()
+	if !strings.Contains(frame, "> blog") || !strings.Contains(frame, i18n.T("setup.states_loading")) {
+		t.Errorf("expected the form to be usable while the states are listed:\n%s", frame)
+	}
+}
+
+// slowBucketList lists the buckets once released
+type slowBucketList struct {
+	*fake.AWS
+	release chan struct{}
+}
+
+func (s slowBucketList) ListBuckets(ctx context.Context) ([]string, error) {
+	<-s.release
+	return s.AWS.ListBuckets(ctx)
+}
+
+func TestSlowLists(t *testing.T) {
+	account := fake.New(fixture)
+	buckets := slowBucketList{AWS: account, release: make(chan struct{})}
+	t.Cleanup(func() { close(buckets.release) })
+	clients := Clients{Lambda: account, AppSync: account, S3: buckets, DynamoDB: account, IAM: account, Caller: account}
+	d := newClientsDriver(t, clients)
+
+	// the form is shown and usable while its lists are looked up
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	frame := d.frame()
+	loading := i18n.T("setup.list_loading", i18n.T("setup.field.backend_bucket"))
+	if !strings.Contains(frame, "> blog") || !strings.Contains(frame, loading) {
+		t.Errorf("expected the form to be usable while the buckets are listed:\n%s", frame)
+	}
+}
+
+func TestDriftEnvironments(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+	d.send(messages.NewCreateResourceMsg(
+		helpers.ResourceIDs.CreateAppSyncAPI,
+		"blog",
+		messages.WithAWSRegion("eu-central-1"),
+		messages.WithBackendBucket("terraform-states"),
+		messages.WithBackendKey("blog/terraform.tfstate"),
+		messages.WithBackendLockTable("terraform-locks"),
+		messages.WithEnvironments([]string{"dev", "prod"}),
+		messages.WithDestination(d.dir),
+	))
+	d.press("esc", "esc")
+
+	d.press("down", "down", "enter", "down", "down", "enter")
+	d.golden("drift_environment_dev")
+
+	d.press("tab")
+	d.golden("drift_environment_prod")
+
+	// the environment compared last is selected again
+	d.press("esc", "enter")
+	d.golden("drift_environment_prod")
+}
+
+// slowAPIs lists the AppSync APIs once released
+type slowAPIs struct {
+	*fake.AWS
+	release chan struct{}
+}
+
+func (s slowAPIs) ListAPIs(ctx context.Context, region string) ([]aws.GraphqlAPI, error) {
+	<-s.release
+	return s.AWS.ListAPIs(ctx, region)
+}
+
+func TestSlowDrift(t *testing.T) {
+	account := fake.New(fixture)
+	apis := slowAPIs{AWS: account, release: make(chan struct{})}
+	t.Cleanup(func() { close(apis.release) })
+	clients := Clients{Lambda: account, AppSync: apis, S3: account, DynamoDB: account, IAM: account, Caller: account}
+	d := newClientsDriver(t, clients)
+	d.createAPI("blog")
+
+	// the menu is usable while the drift is detected
+	d.press("down", "down", "enter", "down", "down", "enter")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("drift.detecting")) {
+		t.Errorf("expected the drift to be detected in the background:\n%s", frame)
+	}
+	d.press("esc")
+	if frame := d.frame(); strings.Contains(frame, i18n.T("drift.detecting")) {
+		t.Errorf("expected the menu to be usable while the drift is detected:\n%s", frame)
+	}
+}
+
+func TestUsedNames(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+	d.createAPI("blog")
+
+	// the entries of the destination are listed once, a project can not replace them
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("validation.exists", "blog")) {
+		t.Errorf("expected blog to be used in the destination:\n%s", frame)
+	}
+
+	// the files and the data sources of the project are used names of a data source
+	d.press("esc", "esc", "enter", "enter")
+	d.typeText("resolvers")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("validation.exists", "resolvers")) {
+		t.Errorf("expected resolvers to be used in the project:\n%s", frame)
+	}
+}
+
+func TestDashboardKeepsSelection(t *testing.T) {
+	d := newDriver(t, fake.New(fixture))
+	d.createAPI("blog")
+
+	// the project is read again when the editor is closed, the schema stays selected
+	d.press("down", "down", "enter", "enter", "down")
+	d.send(messages.EditorClosedMsg{})
+	d.press("enter")
+	if frame := d.frame(); !strings.Contains(frame, "schema.graphql") || strings.Contains(frame, "appsync.tf") {
+		t.Errorf("expected the files of the schema:\n%s", frame)
+	}
+}
+
+// fakeTerraform formats a file and creates the lock file like terraform fmt and init, PATH has only the shell builtins
+const fakeTerraform = `#!/bin/sh
+case "$1" in
+fmt) echo "" >> main.tf ;;
+init) echo "# lock" > .terraform.lock.hcl ;;
+esac
+`
+
+func TestTerraformKeepsRepositoryClean(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	d := newDriver(t, fake.New(fixture))
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte(fakeTerraform), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("PATH", bin)
+
+	d.send(messages.NewCreateResourceMsg(
+		helpers.ResourceIDs.CreateAppSyncAPI,
+		"blog",
+		messages.WithAWSRegion("eu-central-1"),
+		messages.WithBackendBucket("terraform-states"),
+		messages.WithBackendKey("blog/terraform.tfstate"),
+		messages.WithBackendLockTable("terraform-locks"),
+		messages.WithGitInit(true),
+		messages.WithDestination(d.dir),
+	))
+	// run the validation from the result
+	d.press("enter", "enter")
+
+	// the commit may outlast the commands of the driver
+	dir := filepath.Join(d.dir, "blog")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		clean, err := vcs.Clean(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if clean {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the changes of fmt and init to be committed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".terraform.lock.hcl")); err != nil {
+		t.Errorf("expected the lock file to be created: %v", err)
+	}
+}
+
+func TestEnvironmentStateKeys(t *testing.T) {
+	account := fixture
+	account.Buckets = map[string][]string{"terraform-states": {"blog/prod/terraform.tfstate"}}
+	d := newDriver(t, fake.New(account))
+
+	d.press("enter", "down", "enter")
+	d.typeText("blog")
+	if frame := d.frame(); strings.Contains(frame, i18n.T("setup.state_key_used")) {
+		t.Errorf("expected the state key of the project to be free:\n%s", frame)
+	}
+
+	// the environments use their own keys next to the state key of the project
+	for i := 0; i < 9; i++ {
+		d.press("tab")
+	}
+	d.typeText("dev,prod")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("setup.state_key_used")) {
+		t.Errorf("expected the state key of prod to be used:\n%s", frame)
+	}
+}
+
+func TestTerraformEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake terraform is a shell script")
+	}
+	d := newDriver(t, fake.New(fixture))
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "terraform"), []byte("#!/bin/sh\necho \"fake $@\"\n"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("PATH", bin)
+
+	d.send(messages.NewCreateResourceMsg(
+		helpers.ResourceIDs.CreateAppSyncAPI,
+		"blog",
+		messages.WithAWSRegion("eu-central-1"),
+		messages.WithBackendBucket("terraform-states"),
+		messages.WithBackendLockTable("terraform-locks"),
+		messages.WithEnvironments([]string{"dev", "prod"}),
+		messages.WithDestination(d.dir),
+	))
+	// validate, then plan the second environment
+	d.press("enter", "enter")
+	if frame := d.frame(); !strings.Contains(frame, i18n.T("run.environment", "dev · prod")) {
+		t.Fatalf("expected the environments to be offered before the plan:\n%s", frame)
+	}
+	d.press("tab", "enter")
+
+	frame := d.frame()
+	for _, arg := range []string{"-backend-config=environments/prod.s3.tfbackend", "-var-file=environments/prod.tfvars"} {
+		if !strings.Contains(frame, arg) {
+			t.Errorf("expected %s in the plan:\n%s", arg, frame)
+		}
+	}
+}
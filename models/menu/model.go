@@ -6,22 +6,68 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/models/check_column"
+	"github.com/xsevy/terrapi/models/dashboard_column"
+	"github.com/xsevy/terrapi/models/drift_column"
+	"github.com/xsevy/terrapi/models/result_column"
+	"github.com/xsevy/terrapi/models/review_column"
 	"github.com/xsevy/terrapi/models/select_column"
 	"github.com/xsevy/terrapi/models/setup_column"
+	"github.com/xsevy/terrapi/models/terraform_column"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/templates"
+)
+
+// ids of the columns which are not selectable from the select column
+const (
+	terraformColumnID = "terraform"
+	reviewColumnID    = "review"
+	resultColumnID    = "result"
+	// editColumnID focuses the setup column keeping the values of its form
+	editColumnID = "edit"
 )
 
 type MenuModel struct {
-	selectColumn *select_column.SelectColumnModel
-	setupColumn  *setup_column.SetupColumnModel
-	keys         helpers.KeyMap
+	selectColumn    *select_column.SelectColumnModel
+	setupColumn     *setup_column.SetupColumnModel
+	checkColumn     *check_column.CheckColumnModel
+	driftColumn     *drift_column.DriftColumnModel
+	terraformColumn *terraform_column.TerraformColumnModel
+	reviewColumn    *review_column.ReviewColumnModel
+	resultColumn    *result_column.ResultColumnModel
+	dashboardColumn *dashboard_column.DashboardColumnModel
+	layout          styles.Layout
+	keys            helpers.KeyMap
+	// projectDir is the project the resources are added to, the last one created or the one containing
+	// the working directory
+	projectDir string
 }
 
-func NewMenuModel(selectColumn *select_column.SelectColumnModel, setupColumn *setup_column.SetupColumnModel) *MenuModel {
-	return &MenuModel{
-		selectColumn: selectColumn,
-		setupColumn:  setupColumn,
-		keys:         helpers.Keys,
+func NewMenuModel(
+	selectColumn *select_column.SelectColumnModel,
+	setupColumn *setup_column.SetupColumnModel,
+	checkColumn *check_column.CheckColumnModel,
+	driftColumn *drift_column.DriftColumnModel,
+	terraformColumn *terraform_column.TerraformColumnModel,
+	reviewColumn *review_column.ReviewColumnModel,
+	resultColumn *result_column.ResultColumnModel,
+	dashboardColumn *dashboard_column.DashboardColumnModel,
+) *MenuModel {
+	m := &MenuModel{
+		selectColumn:    selectColumn,
+		setupColumn:     setupColumn,
+		checkColumn:     checkColumn,
+		driftColumn:     driftColumn,
+		terraformColumn: terraformColumn,
+		reviewColumn:    reviewColumn,
+		resultColumn:    resultColumn,
+		dashboardColumn: dashboardColumn,
+		keys:            helpers.Keys,
 	}
+	m.SetLayout(styles.DefaultLayout)
+	m.setProject(project.FindRootOrDir("."))
+	return m
 }
 
 func (m *MenuModel) Init() tea.Cmd {
@@ -35,42 +81,158 @@ func (m *MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case m.terraformColumn.GetFocused():
+			_, cmd = m.terraformColumn.Update(msg)
+		case m.reviewColumn.GetFocused():
+			_, cmd = m.reviewColumn.Update(msg)
+		case m.resultColumn.GetFocused():
+			_, cmd = m.resultColumn.Update(msg)
+		case m.dashboardColumn.GetFocused():
+			_, cmd = m.dashboardColumn.Update(msg)
 		case key.Matches(msg, m.keys.Up, m.keys.Down, m.keys.Enter, m.keys.Escape):
 			var newSelectColumn tea.Model
 
-			if m.selectColumn.GetFocused() {
+			switch {
+			case m.selectColumn.GetFocused():
 				newSelectColumn, cmd = m.selectColumn.Update(msg)
 				m.selectColumn = newSelectColumn.(*select_column.SelectColumnModel)
-			} else {
+			case m.checkColumn.GetFocused():
+				_, cmd = m.checkColumn.Update(msg)
+			case m.driftColumn.GetFocused():
+				_, cmd = m.driftColumn.Update(msg)
+			default:
 				newSetupColumn, cmd = m.setupColumn.Update(msg)
 				m.setupColumn = newSetupColumn.(*setup_column.SetupColumnModel)
 			}
 		default:
-			if m.setupColumn.GetFocused() {
+			// the other keys filter and scroll the lists of the columns
+			switch {
+			case m.checkColumn.GetFocused():
+				_, cmd = m.checkColumn.Update(msg)
+			case m.driftColumn.GetFocused():
+				_, cmd = m.driftColumn.Update(msg)
+			case m.setupColumn.GetFocused():
 				newSetupColumn, cmd = m.setupColumn.Update(msg)
 				m.setupColumn = newSetupColumn.(*setup_column.SetupColumnModel)
 			}
 		}
 	case messages.StartSetupMsg:
-		m.switchColumn(msg.ID)
+		cmd = m.switchColumn(msg.ID)
+	case messages.AWSRefreshedMsg, messages.ListsLookedUpMsg, messages.APIsListedMsg, messages.APIExportedMsg, messages.StatesListedMsg:
+		newSetupColumn, cmd = m.setupColumn.Update(msg)
+		m.setupColumn = newSetupColumn.(*setup_column.SetupColumnModel)
+	case drift_column.DriftDetectedMsg:
+		_, cmd = m.driftColumn.Update(msg)
 	case messages.CloseSetupMsg:
 		m.switchColumn("")
+	case messages.ReviewResourceMsg:
+		m.reviewColumn.SetReview(msg)
+		m.switchColumn(reviewColumnID)
+	case messages.EditResourceMsg:
+		m.setupColumn.SelectField(msg.Field)
+		m.switchColumn(editColumnID)
+	case messages.ResourceCreatedMsg:
+		if msg.Err == nil {
+			m.setProject(msg.Dir)
+		}
+		m.resultColumn.SetResult(msg)
+		m.switchColumn(resultColumnID)
+	case messages.StartTerraformMsg:
+		m.terraformColumn.Start(msg.Binary, msg.Dir)
+		m.switchColumn(terraformColumnID)
+	case messages.TerraformOutputMsg, messages.TerraformDoneMsg:
+		_, cmd = m.terraformColumn.Update(msg)
+	case messages.EditorClosedMsg:
+		_, cmd = m.dashboardColumn.Update(msg)
 	}
 	return m, cmd
 }
 
 func (m *MenuModel) View() string {
-	return lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		m.selectColumn.View(),
-		m.setupColumn.View(),
-	)
+	rightColumn := m.setupColumn.View()
+	switch {
+	case m.checkColumn.GetFocused():
+		rightColumn = m.checkColumn.View()
+	case m.driftColumn.GetFocused():
+		rightColumn = m.driftColumn.View()
+	case m.terraformColumn.GetFocused():
+		rightColumn = m.terraformColumn.View()
+	case m.reviewColumn.GetFocused():
+		rightColumn = m.reviewColumn.View()
+	case m.resultColumn.GetFocused():
+		rightColumn = m.resultColumn.View()
+	case m.dashboardColumn.GetFocused():
+		rightColumn = m.dashboardColumn.View()
+	}
+
+	if m.layout.Stacked {
+		return lipgloss.JoinVertical(lipgloss.Left, m.selectColumn.View(), rightColumn)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Left, m.selectColumn.View(), rightColumn)
+}
+
+// SetLayout sizes the columns
+func (m *MenuModel) SetLayout(layout styles.Layout) {
+	m.layout = layout
+	m.selectColumn.SetSize(layout.SelectWidth, layout.SelectHeight)
+	m.setupColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.checkColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.driftColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.terraformColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.reviewColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.resultColumn.SetSize(layout.RightWidth, layout.RightHeight)
+	m.dashboardColumn.SetSize(layout.RightWidth, layout.RightHeight)
 }
 
-func (m *MenuModel) switchColumn(id string) {
-	if id != "" {
-		m.setupColumn.SetID(id)
+// switchColumn focuses the column handling id, or the select column when id is empty.
+// It returns the command revalidating the cached lists of a new form, or detecting the drift.
+func (m *MenuModel) switchColumn(id string) tea.Cmd {
+	m.selectColumn.SetFocused(id == "")
+	m.setupColumn.SetFocused(false)
+	m.checkColumn.SetFocused(false)
+	m.driftColumn.SetFocused(false)
+	m.terraformColumn.SetFocused(false)
+	m.reviewColumn.SetFocused(false)
+	m.resultColumn.SetFocused(false)
+	m.dashboardColumn.SetFocused(false)
+
+	switch id {
+	case "":
+	case helpers.ResourceIDs.CheckProject:
+		m.checkColumn.Check(m.projectDir)
+		m.checkColumn.SetFocused(true)
+	case helpers.ResourceIDs.DriftReport:
+		m.driftColumn.SetFocused(true)
+		return m.driftColumn.Detect(m.projectDir)
+	case helpers.ResourceIDs.ProjectDashboard:
+		m.dashboardColumn.Show(m.projectDir)
+		m.dashboardColumn.SetFocused(true)
+	case terraformColumnID:
+		m.terraformColumn.SetFocused(true)
+	case reviewColumnID:
+		m.reviewColumn.SetFocused(true)
+	case resultColumnID:
+		m.resultColumn.SetFocused(true)
+	case editColumnID:
+		m.setupColumn.SetFocused(true)
+	default:
+		cmd := m.setupColumn.SetID(id)
+		m.setupColumn.SetFocused(true)
+		return cmd
+	}
+	return nil
+}
+
+// setProject makes the project containing dir the current one, the select column shows it with its data sources
+func (m *MenuModel) setProject(dir string) {
+	m.projectDir = project.FindRootOrDir(dir)
+	m.setupColumn.SetProjectDir(m.projectDir)
+
+	manifest, err := project.ReadManifest(m.projectDir)
+	if err != nil {
+		m.selectColumn.SetProject("", nil)
+		return
 	}
-	m.selectColumn.SetFocused(!m.selectColumn.GetFocused())
-	m.setupColumn.SetFocused(!m.setupColumn.GetFocused())
+	dataSources, _ := templates.DataSourceNames(m.projectDir)
+	m.selectColumn.SetProject(manifest.Name, dataSources)
 }
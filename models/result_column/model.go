@@ -0,0 +1,107 @@
+package result_column
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/terraform"
+)
+
+// ResultColumnModel shows the result of a creation with the next steps,
+// enter runs the terraform pipeline in the project when terraform is installed and esc goes back to the menu
+type ResultColumnModel struct {
+	result    messages.ResourceCreatedMsg
+	nextSteps []string
+	binary    string
+	keys      helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewResultColumnModel(focused bool) *ResultColumnModel {
+	m := &ResultColumnModel{
+		keys: helpers.Keys,
+	}
+	m.SetFocused(focused)
+	return m
+}
+
+func (m *ResultColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ResultColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			if m.result.Err != nil {
+				return m, messages.EditResource("")
+			}
+			return m, messages.SwitchColumn("select_column")
+		case key.Matches(msg, m.keys.Enter):
+			if m.result.Err == nil && m.binary != "" {
+				return m, messages.StartTerraform(m.binary, m.result.Dir)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *ResultColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	resource := m.result.Resource
+	name := fmt.Sprintf("%s %s", helpers.ResourceName(resource.ID), resource.ProjectName)
+
+	var lines []string
+	if m.result.Err != nil {
+		lines = append(lines,
+			styles.GetFocusedTitle(i18n.T("result.failed", name), true),
+			"",
+			m.result.Err.Error(),
+			"",
+			i18n.T("result.back_to_form"),
+		)
+		return m.Render(styles.SetupColumnStyleFocused, strings.Join(lines, "\n"), 0, 0)
+	}
+
+	lines = append(lines,
+		styles.GetFocusedTitle(i18n.T("result.done", name), true),
+		"",
+		i18n.T("result.project_dir", m.result.Dir),
+		"",
+		i18n.T("result.next_steps"),
+	)
+	for i, step := range m.nextSteps {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, step))
+	}
+
+	lines = append(lines, "")
+	if m.binary != "" {
+		lines = append(lines, i18n.T("result.run_terraform"))
+	} else {
+		lines = append(lines, i18n.T("result.back_to_menu"))
+	}
+
+	return m.Render(styles.SetupColumnStyleFocused, strings.Join(lines, "\n"), 0, 0)
+}
+
+// SetResult shows the result of a creation
+func (m *ResultColumnModel) SetResult(result messages.ResourceCreatedMsg) {
+	m.result = result
+	m.nextSteps = templates.NextSteps(result.Resource.ID, result.Dir, &result.Resource)
+
+	// the pipeline is optional, without terraform installed there is nothing more to do
+	m.binary, _ = terraform.Find()
+}
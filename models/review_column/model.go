@@ -0,0 +1,158 @@
+package review_column
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/bubbles"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/terraform"
+)
+
+// ReviewColumnModel summarises a resource before its creation. Enter on a value goes back to the
+// form to edit it, enter on the confirm button creates the resource.
+type ReviewColumnModel struct {
+	review   messages.ReviewResourceMsg
+	dir      string
+	files    []templates.PlannedFile
+	warnings []string
+	confirm  *bubbles.ButtonModel
+	// selected is the index of a field, or len(fields) for the confirm button
+	selected navigation.Selected
+	keys     helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewReviewColumnModel(focused bool) *ReviewColumnModel {
+	m := &ReviewColumnModel{
+		keys:    helpers.Keys,
+		confirm: bubbles.NewButtonModel(i18n.T("review.confirm"), true),
+	}
+	m.SetFocused(focused)
+	return m
+}
+
+func (m *ReviewColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *ReviewColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			return m, messages.EditResource("")
+		case key.Matches(msg, m.keys.Up, m.keys.ShiftTab):
+			m.selected.Prev()
+		case key.Matches(msg, m.keys.Down, m.keys.Tab):
+			m.selected.Next(len(m.review.Fields))
+		case key.Matches(msg, m.keys.Enter):
+			if m.confirming() {
+				resource := m.review.Resource
+				return m, func() tea.Msg { return resource }
+			}
+			return m, messages.EditResource(m.review.Fields[m.selected].Field)
+		}
+	}
+
+	if m.confirming() {
+		m.confirm.Focus()
+	} else {
+		m.confirm.Blur()
+	}
+	return m, nil
+}
+
+func (m *ReviewColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	resource := m.review.Resource
+	style := styles.SetupColumnStyleFocused
+
+	// blocks are wrapped one by one to know the lines of the selected one, the column scrolls to it
+	var blocks []string
+	lines := 0
+	add := func(block string) (int, int) {
+		block = m.Wrap(style, block)
+		first := lines
+		lines += lipgloss.Height(block)
+		blocks = append(blocks, block)
+		return first, lines - 1
+	}
+
+	add(styles.GetFocusedTitle(i18n.T("review.title", helpers.ResourceName(resource.ID), resource.ProjectName), true))
+	add("")
+	add(i18n.T("review.project_dir", m.dir))
+	add("")
+
+	first, last := 0, 0
+	for i, field := range m.review.Fields {
+		if i == int(m.selected) {
+			first, last = add(styles.SelectedChoiceStyle.Render(fmt.Sprintf("%s: %s", field.Label, field.Value)))
+		} else {
+			add(styles.ChoiceStyle.Render(fmt.Sprintf("%s: %s", field.Label, field.Value)))
+		}
+	}
+
+	if len(m.files) > 0 {
+		add("")
+		add(i18n.T("review.files"))
+		for _, file := range m.files {
+			add(file.String())
+		}
+	}
+
+	if len(m.warnings) > 0 {
+		add("")
+		add(i18n.T("review.warnings"))
+		for _, warning := range m.warnings {
+			add(styles.FieldErrorStyle.Render("! " + warning))
+		}
+	}
+
+	add("")
+	if button, end := add(m.confirm.View()); m.confirming() {
+		first, last = button, end
+	}
+	add("")
+	add(i18n.T("review.help"))
+
+	return m.Render(style, strings.Join(blocks, "\n"), first, last)
+}
+
+// SetReview shows the resource to review with the files it writes, the confirm button is selected
+func (m *ReviewColumnModel) SetReview(review messages.ReviewResourceMsg) {
+	resource := review.Resource
+	dest := resource.DestinationDir()
+
+	m.review = review
+	m.dir = templates.ProjectDir(resource.ID, dest, &resource)
+	m.warnings = append([]string{}, review.Warnings...)
+	m.selected = navigation.Selected(len(review.Fields))
+	m.confirm.Focus()
+
+	files, err := templates.Plan(resource.ID, dest, &resource)
+	if err != nil {
+		m.warnings = append(m.warnings, i18n.T("review.files_error", err))
+	}
+	m.files = files
+
+	if _, err := terraform.Find(); err != nil {
+		m.warnings = append(m.warnings, i18n.T("review.no_terraform"))
+	}
+}
+
+func (m *ReviewColumnModel) confirming() bool {
+	return int(m.selected) == len(m.review.Fields)
+}
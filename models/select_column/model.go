@@ -1,17 +1,24 @@
 package select_column
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
 	"github.com/xsevy/terrapi/models/select_column_choices"
 	"github.com/xsevy/terrapi/styles"
 )
 
 type SelectColumnModel struct {
 	choices *select_column_choices.SelectColumnChoicesModel
-	keys    helpers.KeyMap
+	// project is the name of the current project, shown under the choices with its data sources
+	project     string
+	dataSources []string
+	keys        helpers.KeyMap
 	models.ColumnModel
 }
 
@@ -44,13 +51,27 @@ func (m *SelectColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *SelectColumnModel) View() string {
-	var content string
 	choicesView := m.choices.View()
+	selected := m.choices.Selected()
+
+	if m.project != "" {
+		lines := []string{"", styles.DisabledChoiceStyle.Render(i18n.T("menu.current_project", m.project))}
+		for _, dataSource := range m.dataSources {
+			lines = append(lines, styles.DisabledChoiceStyle.Render("- "+dataSource))
+		}
+		choicesView = lipgloss.JoinVertical(lipgloss.Left, choicesView, strings.Join(lines, "\n"))
+	}
 
 	if m.GetFocused() {
-		content = styles.SelectColumnStyleFocused.Render(choicesView)
-	} else {
-		content = styles.SelectColumnStyleBlured.Render(choicesView)
+		return m.Render(styles.SelectColumnStyleFocused, choicesView, selected, selected)
 	}
-	return content
+	return m.Render(styles.SelectColumnStyleBlured, choicesView, selected, selected)
+}
+
+// SetProject shows the project the resources are added to, an empty name hides it and disables
+// the choices needing a project
+func (m *SelectColumnModel) SetProject(name string, dataSources []string) {
+	m.project = name
+	m.dataSources = dataSources
+	m.choices.SetDisabled(helpers.ResourceIDs.ProjectDashboard, name == "")
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/i18n"
 	"github.com/xsevy/terrapi/messages"
 	"github.com/xsevy/terrapi/styles"
 )
@@ -40,15 +41,23 @@ func (c selectColumnChoice) GetID() string {
 type SelectColumnChoicesModel struct {
 	keys  helpers.KeyMap
 	stack navigation.NavigationStack
+	// disabled lists the ids of the choices disabled at runtime, like the ones needing a project
+	disabled map[string]bool
 }
 
 func getInitialItems() []navigation.NavigableItem {
-	choices := [2]selectColumnChoice{
+	choices := [3]selectColumnChoice{
 		{name: "AppSync", children: []selectColumnChoice{
-			{name: helpers.ResourceNames[helpers.ResourceIDs.CreateAppSyncDataSource], id: helpers.ResourceIDs.CreateAppSyncDataSource},
-			{name: helpers.ResourceNames[helpers.ResourceIDs.CreateAppSyncAPI], id: helpers.ResourceIDs.CreateAppSyncAPI},
+			{name: helpers.ResourceName(helpers.ResourceIDs.CreateAppSyncDataSource), id: helpers.ResourceIDs.CreateAppSyncDataSource},
+			{name: helpers.ResourceName(helpers.ResourceIDs.CreateAppSyncAPI), id: helpers.ResourceIDs.CreateAppSyncAPI},
+			{name: helpers.ResourceName(helpers.ResourceIDs.ImportAppSyncAPI), id: helpers.ResourceIDs.ImportAppSyncAPI},
 		}},
 		{name: "API Gateway", disabled: true},
+		{name: i18n.T("menu.project"), children: []selectColumnChoice{
+			{name: helpers.ResourceName(helpers.ResourceIDs.ProjectDashboard), id: helpers.ResourceIDs.ProjectDashboard},
+			{name: helpers.ResourceName(helpers.ResourceIDs.CheckProject), id: helpers.ResourceIDs.CheckProject},
+			{name: helpers.ResourceName(helpers.ResourceIDs.DriftReport), id: helpers.ResourceIDs.DriftReport},
+		}},
 	}
 	initialItems := make([]navigation.NavigableItem, len(choices))
 	for i, choice := range choices {
@@ -62,8 +71,9 @@ func NewSelectColumnChoicesModel() *SelectColumnChoicesModel {
 	initialItems := getInitialItems()
 
 	return &SelectColumnChoicesModel{
-		keys:  helpers.Keys,
-		stack: navigation.NewNavigationStack(initialItems),
+		keys:     helpers.Keys,
+		stack:    navigation.NewNavigationStack(initialItems),
+		disabled: map[string]bool{},
 	}
 }
 
@@ -83,7 +93,7 @@ func (m *SelectColumnChoicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			currentNav.Selected.Next(len(currentNav.Items) - 1)
 		case key.Matches(msg, m.keys.Enter):
 			selectedItem := currentNav.Items[currentNav.Selected]
-			if !selectedItem.IsDisabled() {
+			if !m.isDisabled(selectedItem) {
 				if len(selectedItem.GetChildren()) > 0 {
 					m.stack.Push(selectedItem)
 				} else {
@@ -103,7 +113,7 @@ func (m *SelectColumnChoicesModel) View() string {
 
 	for i, choice := range currentNav.Items {
 		var choiceName string
-		if choice.(selectColumnChoice).IsDisabled() {
+		if m.isDisabled(choice) {
 			choiceName = styles.DisabledChoiceStyle.Render(choice.GetName())
 		} else {
 			choiceName = choice.GetName()
@@ -118,3 +128,17 @@ func (m *SelectColumnChoicesModel) View() string {
 
 	return lipgloss.JoinVertical(lipgloss.Top, lines...)
 }
+
+// Selected returns the index of the selected choice, which is also its line in View
+func (m *SelectColumnChoicesModel) Selected() int {
+	return int(m.stack.CurrentItem().Selected)
+}
+
+// SetDisabled disables or enables the choice with the given id
+func (m *SelectColumnChoicesModel) SetDisabled(id string, disabled bool) {
+	m.disabled[id] = disabled
+}
+
+func (m *SelectColumnChoicesModel) isDisabled(item navigation.NavigableItem) bool {
+	return item.IsDisabled() || m.disabled[item.GetID()]
+}
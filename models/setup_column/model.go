@@ -1,47 +1,139 @@
 package setup_column
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/aws/cache"
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/helpers/bubbles"
 	"github.com/xsevy/terrapi/helpers/models"
 	"github.com/xsevy/terrapi/helpers/navigation"
+	"github.com/xsevy/terrapi/i18n"
 	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
 	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/vcs"
 )
 
+const (
+	nameField               = "name"
+	destinationField        = "destination"
+	regionField             = "region"
+	apiField                = "api"
+	backendBucketField      = "backend_bucket"
+	stateKeyField           = "state_key"
+	statesField             = "states"
+	workspaceKeyPrefixField = "workspace_key_prefix"
+	stateLockField          = "state_lock"
+	authorizerField         = "authorizer"
+	environmentsField       = "environments"
+	runtimeField            = "runtime"
+	gitField                = "git"
+	forceField              = "force"
+	submitField             = "submit"
+)
+
+// fieldLabels are the catalog keys naming the values of the fields in the review
+var fieldLabels = map[string]string{
+	nameField:               "setup.field.name",
+	destinationField:        "setup.field.destination",
+	regionField:             "setup.field.region",
+	apiField:                "setup.field.api",
+	backendBucketField:      "setup.field.backend_bucket",
+	stateKeyField:           "setup.field.state_key",
+	workspaceKeyPrefixField: "setup.field.workspace_key_prefix",
+	stateLockField:          "setup.field.state_lock",
+	authorizerField:         "setup.field.authorizer",
+	environmentsField:       "setup.field.environments",
+	runtimeField:            "setup.field.runtime",
+	gitField:                "setup.field.git",
+	forceField:              "setup.field.force",
+}
+
+// catalog keys of the choices of the git and force fields
+const (
+	gitYes      = "setup.git.yes"
+	gitNo       = "setup.git.no"
+	forceAbort  = "setup.force.abort"
+	forceCommit = "setup.force.commit"
+)
+
+var projectNameRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// namedField is a form element which values are looked up by name
+type namedField struct {
+	name    string
+	element navigation.FormField
+}
+
 type SetupColumnModel struct {
+	// ctx cancels the AWS requests of the form
+	ctx            context.Context
 	id             string
 	elements       []navigation.FormField
+	fields         map[string]int
+	touched        map[int]bool
 	lambdaClient   aws.Lambda
 	appsyncClient  aws.AppSync
 	s3Client       aws.S3
 	dynamoDBClient aws.DynamoDB
 	keys           helpers.KeyMap
 	selected       navigation.Selected
+	states         map[string][]string
+	statesBucket   string
+	statesErr      error
+	apis           map[string][]aws.GraphqlAPI
+	apisRegion     string
+	apisErr        error
+	// exporting is set while the API to import is exported, exportErr tells why it could not be
+	exporting bool
+	exportErr error
+	// projectDir is the project data sources are added to by default
+	projectDir string
+	// usedNames are the names the new resource can not have in usedNamesDir, listed when the destination changes
+	usedNames    []string
+	usedNamesDir string
+	// cache serves the lists of the previous sessions while they are revalidated, nil without cache
+	cache      *cache.Cache
+	refreshing bool
+	refreshErr error
 	models.ColumnModel
 }
 
 func NewSetupColumnModel(
+	ctx context.Context,
 	lambdaClient aws.Lambda,
 	appsyncClient aws.AppSync,
 	s3Client aws.S3,
 	dynamoDBClient aws.DynamoDB,
+	cache *cache.Cache,
 	focused bool,
 ) *SetupColumnModel {
 	m := &SetupColumnModel{
+		ctx:            ctx,
 		keys:           helpers.Keys,
 		lambdaClient:   lambdaClient,
 		appsyncClient:  appsyncClient,
 		s3Client:       s3Client,
 		dynamoDBClient: dynamoDBClient,
+		cache:          cache,
 		selected:       0,
+		states:         map[string][]string{},
+		apis:           map[string][]aws.GraphqlAPI{},
+		projectDir:     ".",
 	}
 
 	m.SetFocused(focused)
@@ -53,43 +145,136 @@ func (m *SetupColumnModel) Init() tea.Cmd {
 }
 
 func (m *SetupColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
+	case messages.AWSRefreshedMsg:
+		m.refreshing = false
+		m.refreshErr = msg.Err
+		return m, m.reloadLists()
+	case messages.ListsLookedUpMsg:
+		// the lists of another form are dropped
+		if msg.ID != m.id {
+			return m, nil
+		}
+		return m, m.showLists(msg)
+	case messages.APIsListedMsg:
+		if msg.Err == nil {
+			m.apis[msg.Region] = msg.APIs
+		}
+		if m.hasField(apiField) && msg.Region == m.apisRegion {
+			m.apisErr = msg.Err
+			m.showAPIs()
+			m.refreshSubmit()
+		}
+		return m, nil
+	case messages.StatesListedMsg:
+		if msg.Err == nil {
+			m.states[msg.Bucket] = msg.States
+		}
+		if m.hasField(statesField) && msg.Bucket == m.statesBucket {
+			m.statesErr = msg.Err
+			list := m.list(statesField)
+			value := list.Value()
+			list.SetItems(m.states[msg.Bucket])
+			list.Select(value)
+			m.refreshStatesTitle()
+		}
+		return m, nil
+	case messages.APIExportedMsg:
+		if !m.exporting {
+			return m, nil
+		}
+		m.exporting = false
+		// the export is dropped when another API was chosen meanwhile
+		if api, ok := m.selectedAPI(); !ok || api.ID != msg.APIID || m.value(regionField) != msg.Region {
+			return m, nil
+		}
+		if msg.Err != nil {
+			m.exportErr = msg.Err
+			return m, nil
+		}
+
+		resource := messages.NewCreateResourceMsg(
+			m.id,
+			m.projectName(),
+			messages.WithAWSRegion(msg.Region),
+			messages.WithBackendBucket(m.value(backendBucketField)),
+			messages.WithBackendKey(m.stateKey()),
+			messages.WithBackendLockTable(m.value(stateLockField)),
+			messages.WithAppSyncExport(msg.Export),
+			messages.WithGitInit(m.value(gitField) == i18n.T(gitYes)),
+			messages.WithDestination(m.value(destinationField)),
+		)
+		return m, messages.ReviewResource(resource, m.reviewFields(), m.warnings())
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.keys.Escape):
 			return m, messages.SwitchColumn("select_column")
+		case key.Matches(msg, m.keys.Refresh):
+			if m.refreshing {
+				return m, nil
+			}
+			m.cache.Expire()
+			return m, m.revalidate()
 		case key.Matches(msg, m.keys.Tab):
+			m.touched[int(m.selected)] = true
 			m.selected.Next(len(m.elements) - 1)
 		case key.Matches(msg, m.keys.ShiftTab):
+			m.touched[int(m.selected)] = true
 			m.selected.Prev()
 		case key.Matches(msg, m.keys.Enter):
 			if _, ok := m.elements[m.selected].(*bubbles.ButtonModel); ok {
+				if !m.valid() {
+					// show the errors of the fields the user skipped
+					for i := range m.elements {
+						m.touched[i] = true
+					}
+					return m, nil
+				}
+
+				var resource messages.CreateResourceMsg
 				switch m.id {
 				case helpers.ResourceIDs.CreateAppSyncAPI:
-					cmd = messages.CreateResource(
+					resource = messages.NewCreateResourceMsg(
 						m.id,
-						m.elements[0].Value(),
-						messages.WithAWSRegion(m.elements[1].Value()),
-						messages.WithBackendBucket(m.elements[2].Value()),
-						messages.WithBackendLockTable(m.elements[3].Value()),
-						messages.WithAuthorizerLambdaFunction(m.elements[4].Value()),
+						m.value(nameField),
+						messages.WithAWSRegion(m.value(regionField)),
+						messages.WithBackendBucket(m.value(backendBucketField)),
+						messages.WithBackendKey(m.stateKey()),
+						messages.WithBackendWorkspaceKeyPrefix(m.value(workspaceKeyPrefixField)),
+						messages.WithBackendLockTable(m.value(stateLockField)),
+						messages.WithAuthorizerLambdaFunction(m.value(authorizerField)),
+						messages.WithEnvironments(parseEnvironments(m.value(environmentsField))),
+						messages.WithGitInit(m.value(gitField) == i18n.T(gitYes)),
+						messages.WithDestination(m.value(destinationField)),
 					)
+				case helpers.ResourceIDs.ImportAppSyncAPI:
+					api, ok := m.selectedAPI()
+					if !ok || m.exporting {
+						return m, nil
+					}
+					// APIExportedMsg continues to the review
+					return m, m.exportAPI(api)
 				case helpers.ResourceIDs.CreateAppSyncDataSource:
-					cmd = messages.CreateResource(
+					if m.value(forceField) == i18n.T(forceAbort) {
+						return m, messages.SwitchColumn("select_column")
+					}
+					resource = messages.NewCreateResourceMsg(
 						m.id,
-						m.elements[0].Value(),
-						messages.WithLambdaRuntime(m.elements[1].Value()),
+						m.value(nameField),
+						messages.WithLambdaRuntime(m.value(runtimeField)),
+						messages.WithForce(m.value(forceField) == i18n.T(forceCommit)),
+						messages.WithDestination(m.value(destinationField)),
 					)
 				}
 
-				return m, cmd
+				return m, messages.ReviewResource(resource, m.reviewFields(), m.warnings())
 			}
 		}
 	}
 
+	api := m.value(apiField)
 	for i := range m.elements {
 		if i == int(m.selected) {
 			updatedModel, cmd := m.elements[i].Update(msg)
@@ -106,101 +291,698 @@ func (m *SetupColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.elements[i].Blur()
 		}
 	}
+
+	if m.hasField(apiField) {
+		cmds = append(cmds, m.refreshAPIs())
+		if m.value(apiField) != api {
+			m.exportErr = nil
+		}
+	}
+	if m.hasField(statesField) {
+		cmds = append(cmds, m.refreshStates())
+	}
+	m.refreshUsedNames()
+	m.refreshSubmit()
+
 	return m, tea.Batch(cmds...)
 }
 
 func (m *SetupColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
 	views := []string{}
-	for _, element := range m.elements {
-		views = append(views, element.View())
+	// lines of the selected element, the column scrolls to keep it visible
+	line, first, last := 0, 0, 0
+	if status := m.status(); status != "" {
+		status = m.Wrap(styles.SetupColumnStyleFocused, status)
+		line += lipgloss.Height(status) + 1
+		views = append(views, status)
 	}
-	content := strings.Join(views, "\n\n")
+	for i, element := range m.elements {
+		view := element.View()
+		// errors are shown once the user left the field or typed into it, not on an untouched form
+		if err := element.Validate(); err != nil && (m.touched[i] || element.Value() != "") {
+			view = lipgloss.JoinVertical(lipgloss.Left, view, styles.FieldErrorStyle.Render(err.Error()))
+		}
+		if m.exportErr != nil && m.hasField(apiField) && i == m.fields[apiField] {
+			view = lipgloss.JoinVertical(lipgloss.Left, view, styles.FieldErrorStyle.Render(i18n.T("setup.export_error", m.exportErr)))
+		}
+		view = m.Wrap(styles.SetupColumnStyleFocused, view)
 
-	if m.GetFocused() {
-		content = styles.SetupColumnStyleFocused.Render(content)
-	} else {
-		content = styles.SetupColumnStyleBlured.Render("")
+		height := lipgloss.Height(view)
+		if i == int(m.selected) {
+			first, last = line, line+height-1
+		}
+		// the elements are separated by an empty line
+		line += height + 1
+		views = append(views, view)
 	}
-	return content
+
+	return m.Render(styles.SetupColumnStyleFocused, strings.Join(views, "\n\n"), first, last)
 }
 
-func (m *SetupColumnModel) SetID(id string) {
+// SetID shows the form of the resource id, it returns the command revalidating the lists served from
+// an expired cache
+func (m *SetupColumnModel) SetID(id string) tea.Cmd {
 	m.id = id
-	m.setElements()
+	m.refreshErr = nil
+	m.exporting, m.exportErr = false, nil
+	cmd := m.setElements()
+
+	if m.refreshing || !m.cache.Stale() {
+		return cmd
+	}
+	return tea.Batch(cmd, m.revalidate())
 }
 
-func (m *SetupColumnModel) setElements() {
-	var wg sync.WaitGroup
+// status tells that the lists are being revalidated, or why they could not be
+func (m *SetupColumnModel) status() string {
+	switch {
+	case m.exporting:
+		return styles.DisabledChoiceStyle.Render(i18n.T("setup.exporting"))
+	case m.refreshing:
+		return styles.DisabledChoiceStyle.Render(i18n.T("setup.refreshing"))
+	case m.refreshErr != nil:
+		return styles.FieldErrorStyle.Render(i18n.T("setup.refresh_error", m.refreshErr))
+	}
+	return ""
+}
 
-	switch m.id {
-	case helpers.ResourceIDs.CreateAppSyncAPI:
-		var lambdaFunctions, appsyncRegions, s3Buckets, dynamoDBTables []string
-		var lambdaFunctionsErr, appsyncRegionsErr, s3BucketsErr, dynamoDBTablesErr error
+// revalidate lists the stale lookups again in the background, AWSRefreshedMsg reloads the lists
+func (m *SetupColumnModel) revalidate() tea.Cmd {
+	m.refreshing = true
+	ctx, c := m.ctx, m.cache
+	return func() tea.Msg {
+		return messages.AWSRefreshedMsg{Err: c.Revalidate(ctx)}
+	}
+}
 
-		wg.Add(4)
+// reloadLists lists the resources of the form again, the selected items stay selected
+func (m *SetupColumnModel) reloadLists() tea.Cmd {
+	var fields []string
+	for _, name := range listFields {
+		if m.hasField(name) {
+			fields = append(fields, name)
+		}
+	}
 
-		go func() {
-			defer wg.Done()
-			lambdaFunctions, lambdaFunctionsErr = m.lambdaClient.ListFunctions()
-			if lambdaFunctionsErr != nil {
-				panic(lambdaFunctionsErr)
-			}
-		}()
+	cmds := []tea.Cmd{m.lookupLists(true, fields...)}
+	if m.hasField(statesField) {
+		// the other buckets are listed again once selected, the states of this one are shown until they are listed
+		states, ok := m.states[m.statesBucket]
+		m.states = map[string][]string{}
+		if ok {
+			m.states[m.statesBucket] = states
+			cmds = append(cmds, m.listStates(m.statesBucket))
+		}
+		cmds = append(cmds, m.refreshStates())
+	}
+	if m.hasField(apiField) {
+		// the other regions are listed again once selected, the APIs of this one are shown until they are listed
+		apis, ok := m.apis[m.apisRegion]
+		m.apis = map[string][]aws.GraphqlAPI{}
+		if ok {
+			m.apis[m.apisRegion] = apis
+			cmds = append(cmds, m.listAPIs(m.apisRegion))
+		}
+		cmds = append(cmds, m.refreshAPIs())
+	}
+	m.refreshSubmit()
+	return tea.Batch(cmds...)
+}
 
-		go func() {
-			defer wg.Done()
-			appsyncRegions, appsyncRegionsErr = m.appsyncClient.Regions()
-			if appsyncRegionsErr != nil {
-				panic(appsyncRegionsErr)
-			}
-		}()
+// listFields are the lists filled from AWS when the form is shown
+var listFields = []string{regionField, backendBucketField, stateLockField, authorizerField, runtimeField}
 
-		go func() {
-			defer wg.Done()
-			s3Buckets, s3BucketsErr = m.s3Client.ListBuckets()
-			if s3BucketsErr != nil {
-				panic(s3BucketsErr)
-			}
-		}()
+// listTitles are the catalog keys of the titles of listFields
+var listTitles = map[string]string{
+	regionField:        "setup.region",
+	backendBucketField: "setup.backend_bucket",
+	stateLockField:     "setup.state_lock",
+	authorizerField:    "setup.authorizer",
+	runtimeField:       "setup.runtime",
+}
+
+// lookupLists lists the items of the fields in the background, ListsLookedUpMsg shows them
+func (m *SetupColumnModel) lookupLists(reload bool, fields ...string) tea.Cmd {
+	ctx, id := m.ctx, m.id
+	lookups := map[string]func(context.Context) ([]string, error){
+		regionField:        m.appsyncClient.Regions,
+		backendBucketField: m.s3Client.ListBuckets,
+		stateLockField:     m.dynamoDBClient.ListTables,
+		authorizerField:    m.lambdaClient.ListFunctions,
+		runtimeField:       m.lambdaClient.ListRuntimes,
+	}
+
+	return func() tea.Msg {
+		lists, errs := lookup(ctx, lookups, fields...)
+		return messages.ListsLookedUpMsg{ID: id, Lists: lists, Errs: errs, Reload: reload}
+	}
+}
 
-		go func() {
+// lookup lists the items of the fields concurrently, the failing lookups give no items and their errors by field.
+// A cancelled or timed out request is a failing lookup, the form is still shown.
+func lookup(
+	ctx context.Context,
+	lookups map[string]func(context.Context) ([]string, error),
+	fields ...string,
+) (map[string][]string, map[string]error) {
+	lists := make(map[string][]string, len(fields))
+	errs := map[string]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, field := range fields {
+		wg.Add(1)
+		go func(field string) {
 			defer wg.Done()
-			dynamoDBTables, dynamoDBTablesErr = m.dynamoDBClient.ListTables()
-			if dynamoDBTablesErr != nil {
-				panic(dynamoDBTablesErr)
+			items, err := lookups[field](ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[field] = err
+				return
 			}
-		}()
+			lists[field] = items
+		}(field)
+	}
+	wg.Wait()
 
-		wg.Wait()
+	return lists, errs
+}
 
-		m.elements = []navigation.FormField{
-			bubbles.NewTextInput("Name:", "name", 32),
-			bubbles.NewListModel("Region:", appsyncRegions, true, false),
-			bubbles.NewListModel("Backend bucket:", s3Buckets, true, false),
-			bubbles.NewListModel("State lock:", dynamoDBTables, true, false),
-			bubbles.NewListModel("Authorizer function:", lambdaFunctions, true, false),
-			bubbles.NewButtonModel("Submit", false),
+// showLists fills the lists looked up by lookupLists, the selected items stay selected
+func (m *SetupColumnModel) showLists(msg messages.ListsLookedUpMsg) tea.Cmd {
+	for _, name := range listFields {
+		if !m.hasField(name) {
+			continue
+		}
+		if err, ok := msg.Errs[name]; ok {
+			if msg.Reload {
+				// the previous items are better than none
+				m.refreshErr = errors.Join(m.refreshErr, err)
+			} else {
+				m.setListError(name, err)
+			}
+			continue
+		}
+		items, ok := msg.Lists[name]
+		if !ok {
+			continue
 		}
+		list := m.list(name)
+		value := list.Value()
+		list.SetItems(items)
+		list.Select(value)
+		m.setListError(name, nil)
+	}
+
+	// the states and the APIs depend on the selected bucket and region
+	var cmds []tea.Cmd
+	if m.hasField(statesField) {
+		cmds = append(cmds, m.refreshStates())
+	}
+	if m.hasField(apiField) {
+		cmds = append(cmds, m.refreshAPIs())
+	}
+	m.refreshSubmit()
+	return tea.Batch(cmds...)
+}
+
+// setListsLoading tells that the items of the named lists are being listed
+func (m *SetupColumnModel) setListsLoading(names ...string) {
+	for _, name := range names {
+		m.list(name).SetTitle(i18n.T("setup.list_loading", i18n.T(fieldLabels[name])))
+	}
+}
+
+// setListError shows err in the title of the named list, a nil err restores the title
+func (m *SetupColumnModel) setListError(name string, err error) {
+	title := i18n.T(listTitles[name])
+	if err != nil {
+		title = i18n.T("setup.list_error", i18n.T(fieldLabels[name]), err)
+	}
+	m.list(name).SetTitle(title)
+}
+
+// SetProjectDir sets the project the next data sources are added to
+func (m *SetupColumnModel) SetProjectDir(dir string) {
+	m.projectDir = dir
+}
+
+func (m *SetupColumnModel) setElements() tea.Cmd {
+	var cmd tea.Cmd
+	// the lists filled from AWS are shown empty until lookupLists listed them
+	var lookups []string
+
+	switch m.id {
+	case helpers.ResourceIDs.CreateAppSyncAPI:
+		lookups = []string{regionField, backendBucketField, stateLockField, authorizerField}
+		m.setFields(
+			namedField{nameField, bubbles.NewTextInput(i18n.T("setup.name"), i18n.T("setup.name_placeholder"), 32)},
+			namedField{destinationField, bubbles.NewDirectoryPicker(i18n.T("setup.destination"), ".", false)},
+			namedField{regionField, bubbles.NewListModel(i18n.T("setup.region"), []string{}, true, false)},
+			namedField{backendBucketField, bubbles.NewListModel(i18n.T("setup.backend_bucket"), []string{}, true, false)},
+			namedField{stateKeyField, bubbles.NewTextInput(i18n.T("setup.state_key"), defaultStateKey("<name>"), 256)},
+			namedField{statesField, bubbles.NewListModel(i18n.T("setup.states"), []string{}, true, false)},
+			namedField{workspaceKeyPrefixField, bubbles.NewTextInput(i18n.T("setup.workspace_key_prefix"), "env:", 64)},
+			namedField{stateLockField, bubbles.NewListModel(i18n.T("setup.state_lock"), []string{}, true, false)},
+			namedField{authorizerField, bubbles.NewListModel(i18n.T("setup.authorizer"), []string{}, true, false)},
+			namedField{environmentsField, bubbles.NewTextInput(i18n.T("setup.environments"), "dev,stage,prod", 128)},
+			namedField{gitField, bubbles.NewListModel(i18n.T("setup.git"), []string{i18n.T(gitYes), i18n.T(gitNo)}, false, false)},
+			namedField{submitField, bubbles.NewButtonModel(i18n.T("setup.submit"), false)},
+		)
+		m.setValidators(nameField, navigation.Required(), navigation.AWSName(), navigation.Unique(m.existingNames))
+		m.setValidators(regionField, navigation.Required())
+		m.setValidators(backendBucketField, navigation.Required())
+		m.setValidators(stateLockField, navigation.Required())
+		m.setValidators(authorizerField, navigation.Required())
+		m.setValidators(stateKeyField, navigation.S3Key())
+		m.setValidators(workspaceKeyPrefixField, navigation.S3Key())
+		m.setValidators(environmentsField, validateEnvironments)
+		m.setListsLoading(lookups...)
+		m.statesBucket = ""
+		cmd = m.refreshStates()
+	case helpers.ResourceIDs.ImportAppSyncAPI:
+		lookups = []string{regionField, backendBucketField, stateLockField}
+		m.setFields(
+			namedField{regionField, bubbles.NewListModel(i18n.T("setup.region"), []string{}, true, false)},
+			namedField{apiField, bubbles.NewListModel(i18n.T("setup.api"), []string{}, true, false)},
+			namedField{nameField, bubbles.NewTextInput(i18n.T("setup.name_optional"), i18n.T("setup.name_of_api"), 32)},
+			namedField{destinationField, bubbles.NewDirectoryPicker(i18n.T("setup.destination"), ".", false)},
+			namedField{backendBucketField, bubbles.NewListModel(i18n.T("setup.backend_bucket"), []string{}, true, false)},
+			namedField{stateKeyField, bubbles.NewTextInput(i18n.T("setup.state_key"), defaultStateKey("<name>"), 256)},
+			namedField{statesField, bubbles.NewListModel(i18n.T("setup.states"), []string{}, true, false)},
+			namedField{stateLockField, bubbles.NewListModel(i18n.T("setup.state_lock"), []string{}, true, false)},
+			namedField{gitField, bubbles.NewListModel(i18n.T("setup.git"), []string{i18n.T(gitYes), i18n.T(gitNo)}, false, false)},
+			namedField{submitField, bubbles.NewButtonModel(i18n.T("setup.submit"), false)},
+		)
+		m.setValidators(regionField, navigation.Required())
+		m.setValidators(apiField, navigation.Required())
+		m.setValidators(nameField, navigation.AWSName(), navigation.Unique(m.existingNames))
+		m.setValidators(backendBucketField, navigation.Required())
+		m.setValidators(stateKeyField, navigation.S3Key())
+		m.setValidators(stateLockField, navigation.Required())
+		m.setListsLoading(lookups...)
+		m.apisRegion = ""
+		m.statesBucket = ""
+		cmd = tea.Batch(m.refreshAPIs(), m.refreshStates())
 	case helpers.ResourceIDs.CreateAppSyncDataSource:
-		var lambdaRuntimes []string
-		var lambdaRuntimesErr error
+		lookups = []string{runtimeField}
 
-		wg.Add(1)
+		root := project.FindRootOrDir(m.projectDir)
+		fields := []namedField{
+			{nameField, bubbles.NewTextInput(i18n.T("setup.name"), i18n.T("setup.name_placeholder"), 32)},
+			{destinationField, bubbles.NewDirectoryPicker(i18n.T("setup.project"), root, false)},
+			{runtimeField, bubbles.NewListModel(i18n.T("setup.runtime"), []string{}, true, false)},
+		}
+		if dirtyRepository(root) {
+			fields = append(fields, namedField{
+				forceField,
+				bubbles.NewListModel(i18n.T("setup.force"), []string{i18n.T(forceAbort), i18n.T(forceCommit)}, false, false),
+			})
+		}
+		m.setFields(append(fields, namedField{submitField, bubbles.NewButtonModel(i18n.T("setup.submit"), false)})...)
+		m.setValidators(nameField, navigation.Required(), navigation.AWSName(), navigation.Unique(m.existingNames))
+		m.setValidators(destinationField, validateProject)
+		m.setValidators(runtimeField, navigation.Required())
+		m.setListsLoading(lookups...)
+	}
 
-		go func() {
-			defer wg.Done()
-			lambdaRuntimes, lambdaRuntimesErr = m.lambdaClient.ListRuntimes()
-			if lambdaRuntimesErr != nil {
-				panic(lambdaRuntimesErr)
-			}
-		}()
+	m.listUsedNames()
+	m.refreshSubmit()
+	return tea.Batch(cmd, m.lookupLists(false, lookups...))
+}
 
-		wg.Wait()
+// defaultStateKey keeps the state of each project under its own prefix in a shared bucket
+func defaultStateKey(projectName string) string {
+	return fmt.Sprintf("%s/terraform.tfstate", projectName)
+}
+
+// setFields replaces the form elements
+func (m *SetupColumnModel) setFields(fields ...namedField) {
+	m.elements = make([]navigation.FormField, 0, len(fields))
+	m.fields = make(map[string]int, len(fields))
+	m.touched = map[int]bool{}
+	m.selected = 0
+
+	for i, f := range fields {
+		m.elements = append(m.elements, f.element)
+		m.fields[f.name] = i
+	}
+}
+
+func (m *SetupColumnModel) hasField(name string) bool {
+	_, ok := m.fields[name]
+	return ok
+}
+
+// value returns the value of the named element, or an empty string when the form has no such element
+func (m *SetupColumnModel) value(name string) string {
+	if !m.hasField(name) {
+		return ""
+	}
+	return m.elements[m.fields[name]].Value()
+}
+
+// list returns the named list element
+func (m *SetupColumnModel) list(name string) *bubbles.ListModel {
+	return m.elements[m.fields[name]].(*bubbles.ListModel)
+}
+
+func (m *SetupColumnModel) setValidators(name string, validators ...navigation.Validator) {
+	m.elements[m.fields[name]].SetValidators(validators...)
+}
 
-		m.elements = []navigation.FormField{
-			bubbles.NewTextInput("Name:", "name", 32),
-			bubbles.NewListModel("Runtime", lambdaRuntimes, true, false),
-			bubbles.NewButtonModel("Submit", false),
+// valid reports whether every field of the form accepts its value
+func (m *SetupColumnModel) valid() bool {
+	for _, element := range m.elements {
+		if element.Validate() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshSubmit disables the submit button until the form is valid
+func (m *SetupColumnModel) refreshSubmit() {
+	if m.hasField(submitField) {
+		m.elements[m.fields[submitField]].(*bubbles.ButtonModel).SetDisabled(!m.valid())
+	}
+}
+
+// refreshUsedNames lists the names used in the destination again when it changed
+func (m *SetupColumnModel) refreshUsedNames() {
+	if m.value(destinationField) != m.usedNamesDir {
+		m.listUsedNames()
+	}
+}
+
+// listUsedNames lists the names used in the destination, the name is validated against them on every key
+func (m *SetupColumnModel) listUsedNames() {
+	m.usedNamesDir = m.value(destinationField)
+	switch {
+	case !m.hasField(nameField):
+		m.usedNames = nil
+	case m.id == helpers.ResourceIDs.CreateAppSyncDataSource:
+		m.usedNames = m.usedDataSourceNames()
+	default:
+		m.usedNames = m.destinationEntries()
+	}
+}
+
+// existingNames returns the names listed by listUsedNames
+func (m *SetupColumnModel) existingNames() []string {
+	return m.usedNames
+}
+
+// destinationEntries lists the destination directory, a new project can not replace one of its entries
+func (m *SetupColumnModel) destinationEntries() []string {
+	entries, err := os.ReadDir(m.value(destinationField))
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// usedDataSourceNames returns the names already used in the project chosen for a data source
+func (m *SetupColumnModel) usedDataSourceNames() []string {
+	names, err := templates.UsedDataSourceNames(m.value(destinationField))
+	if err != nil {
+		return nil
+	}
+	return names
+}
+
+// validateProject rejects a directory outside of a terrapi project
+func validateProject(dir string) error {
+	if _, err := project.FindRoot(dir); err != nil {
+		return errors.New(i18n.T("setup.not_a_project"))
+	}
+	return nil
+}
+
+// validateEnvironments checks every environment name, they are used in resource and file names
+func validateEnvironments(value string) error {
+	seen := map[string]bool{}
+	for _, env := range parseEnvironments(value) {
+		if err := navigation.AWSName()(env); err != nil {
+			return errors.New(i18n.T("setup.invalid_environment", env, err))
+		}
+		if seen[env] {
+			return errors.New(i18n.T("setup.duplicate_environment", env))
 		}
+		seen[env] = true
+	}
+	return nil
+}
+
+// SelectField selects the named field, used to edit a value from the review
+func (m *SetupColumnModel) SelectField(name string) {
+	if m.hasField(name) {
+		m.selected = navigation.Selected(m.fields[name])
+	}
+}
+
+// reviewFields lists the values of the form in its order, empty optional values are left out
+func (m *SetupColumnModel) reviewFields() []messages.ReviewField {
+	names := make([]string, len(m.elements))
+	for name, i := range m.fields {
+		names[i] = name
+	}
+
+	var fields []messages.ReviewField
+	for _, name := range names {
+		label, ok := fieldLabels[name]
+		if !ok {
+			continue
+		}
+
+		value := m.value(name)
+		switch name {
+		case nameField:
+			value = m.projectName()
+		case stateKeyField:
+			value = m.stateKey()
+		}
+		if value != "" {
+			fields = append(fields, messages.ReviewField{Field: name, Label: i18n.T(label), Value: value})
+		}
+	}
+	return fields
+}
+
+// warnings lists what the user should know before the creation
+func (m *SetupColumnModel) warnings() []string {
+	var warnings []string
+	if m.hasField(statesField) {
+		for _, key := range m.usedStateKeys() {
+			warnings = append(warnings, i18n.T("setup.state_key_used_in", key, m.statesBucket))
+		}
+	}
+	if m.value(forceField) == i18n.T(forceCommit) {
+		warnings = append(warnings, i18n.T("setup.commit_warning"))
+	}
+	return warnings
+}
+
+// dirtyRepository reports whether the project in dir commits its changes to git and has uncommitted changes
+func dirtyRepository(dir string) bool {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil || !manifest.Git {
+		return false
+	}
+
+	clean, err := vcs.Clean(dir)
+	return err == nil && !clean
+}
+
+// parseEnvironments splits the comma separated environment names, no names means a single environment layout
+func parseEnvironments(value string) []string {
+	var environments []string
+	for _, env := range strings.Split(value, ",") {
+		if env = strings.TrimSpace(env); env != "" {
+			environments = append(environments, env)
+		}
+	}
+
+	return environments
+}
+
+// stateKey returns the state key given in the form or the default one for the project
+func (m *SetupColumnModel) stateKey() string {
+	if key := strings.TrimSpace(m.value(stateKeyField)); key != "" {
+		return key
+	}
+
+	return defaultStateKey(m.projectName())
+}
+
+// stateKeys returns the keys of the states of the project, projects using environments have one
+// per environment next to the state key
+func (m *SetupColumnModel) stateKeys() []string {
+	environments := parseEnvironments(m.value(environmentsField))
+	if len(environments) == 0 {
+		return []string{m.stateKey()}
+	}
+
+	keys := make([]string, 0, len(environments))
+	for _, env := range environments {
+		keys = append(keys, templates.EnvironmentBackendKey(m.projectName(), m.stateKey(), env))
+	}
+	return keys
+}
+
+// usedStateKeys returns the keys of stateKeys already used in the listed states of the bucket
+func (m *SetupColumnModel) usedStateKeys() []string {
+	var used []string
+	for _, key := range m.stateKeys() {
+		if slices.Contains(m.states[m.statesBucket], key) {
+			used = append(used, key)
+		}
+	}
+	return used
+}
+
+// projectName returns the name given in the form, an imported API defaults to its own name
+func (m *SetupColumnModel) projectName() string {
+	if name := strings.TrimSpace(m.value(nameField)); name != "" || m.id != helpers.ResourceIDs.ImportAppSyncAPI {
+		return name
+	}
+
+	api, ok := m.selectedAPI()
+	if !ok {
+		return ""
+	}
+	return strings.Trim(projectNameRegex.ReplaceAllString(strings.ToLower(api.Name), "_"), "_")
+}
+
+// apiName is shown in the list of APIs to import, the ID tells apart APIs with the same name
+func apiName(api aws.GraphqlAPI) string {
+	return fmt.Sprintf("%s (%s)", api.Name, api.ID)
+}
+
+// selectedAPI returns the API chosen in the list of APIs to import
+func (m *SetupColumnModel) selectedAPI() (aws.GraphqlAPI, bool) {
+	selected := m.value(apiField)
+	for _, api := range m.apis[m.apisRegion] {
+		if apiName(api) == selected {
+			return api, true
+		}
+	}
+
+	return aws.GraphqlAPI{}, false
+}
+
+// refreshAPIs shows the APIs of the selected region when it changes, the APIs of a region not listed yet
+// are listed in the background
+func (m *SetupColumnModel) refreshAPIs() tea.Cmd {
+	region := m.value(regionField)
+	if region == m.apisRegion {
+		return nil
+	}
+	m.apisRegion = region
+	m.apisErr = nil
+	m.exportErr = nil
+	m.showAPIs()
+
+	if _, ok := m.apis[region]; ok || region == "" {
+		return nil
+	}
+	return m.listAPIs(region)
+}
+
+// listAPIs lists the APIs of region, APIsListedMsg shows them
+func (m *SetupColumnModel) listAPIs(region string) tea.Cmd {
+	ctx, client := m.ctx, m.appsyncClient
+	return func() tea.Msg {
+		apis, err := client.ListAPIs(ctx, region)
+		return messages.APIsListedMsg{Region: region, APIs: apis, Err: err}
+	}
+}
+
+// showAPIs fills the list with the APIs of the selected region, the selected API stays selected
+func (m *SetupColumnModel) showAPIs() {
+	apis, listed := m.apis[m.apisRegion]
+	names := make([]string, 0, len(apis))
+	for _, api := range apis {
+		names = append(names, apiName(api))
+	}
+
+	title := i18n.T("setup.api")
+	switch {
+	case m.apisErr != nil:
+		title = i18n.T("setup.api_error", m.apisErr)
+	case !listed && m.apisRegion != "":
+		title = i18n.T("setup.api_loading")
+	}
+
+	list := m.list(apiField)
+	value := list.Value()
+	list.SetTitle(title)
+	list.SetItems(names)
+	list.Select(value)
+}
+
+// exportAPI exports api in the background, APIExportedMsg carries the export
+func (m *SetupColumnModel) exportAPI(api aws.GraphqlAPI) tea.Cmd {
+	m.exporting = true
+	m.exportErr = nil
+	ctx, client, region := m.ctx, m.appsyncClient, m.value(regionField)
+	return func() tea.Msg {
+		export, err := client.ExportAPI(ctx, region, api.ID)
+		return messages.APIExportedMsg{Region: region, APIID: api.ID, Export: export, Err: err}
+	}
+}
+
+// refreshStates shows the states stored in the selected backend bucket when it changes, the states of a bucket
+// not listed yet are listed in the background
+func (m *SetupColumnModel) refreshStates() tea.Cmd {
+	bucket := m.value(backendBucketField)
+
+	var cmd tea.Cmd
+	if bucket != m.statesBucket {
+		m.statesBucket = bucket
+		m.statesErr = nil
+		m.list(statesField).SetItems(m.states[bucket])
+
+		if _, ok := m.states[bucket]; !ok && bucket != "" {
+			cmd = m.listStates(bucket)
+		}
+	}
+
+	m.refreshStatesTitle()
+	return cmd
+}
+
+// listStates lists the states of bucket, StatesListedMsg shows them
+func (m *SetupColumnModel) listStates(bucket string) tea.Cmd {
+	ctx, client := m.ctx, m.s3Client
+	return func() tea.Msg {
+		states, err := client.ListStates(ctx, bucket)
+		return messages.StatesListedMsg{Bucket: bucket, States: states, Err: err}
+	}
+}
+
+// refreshStatesTitle tells whether the states are listed and warns about a colliding key
+func (m *SetupColumnModel) refreshStatesTitle() {
+	_, listed := m.states[m.statesBucket]
+
+	title := i18n.T("setup.states")
+	switch {
+	case m.statesErr != nil:
+		title = i18n.T("setup.states_error", m.statesErr)
+	case !listed && m.statesBucket != "":
+		title = i18n.T("setup.states_loading")
+	case len(m.usedStateKeys()) > 0:
+		title = i18n.T("setup.state_key_used")
 	}
+	m.list(statesField).SetTitle(title)
 }
@@ -0,0 +1,229 @@
+package terraform_column
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/models"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+	"github.com/xsevy/terrapi/styles"
+	"github.com/xsevy/terrapi/templates"
+	"github.com/xsevy/terrapi/terraform"
+	"github.com/xsevy/terrapi/vcs"
+)
+
+// commitMessage is the message of the commit of the files formatted and created by the pipeline
+const commitMessage = "Format and initialize the terraform project"
+
+// space taken around the output by the column padding, the title and the prompt
+const (
+	viewportHorizontalMargin = 4
+	viewportVerticalMargin   = 8
+)
+
+type state int
+
+const (
+	stateConfirmValidate state = iota
+	stateValidating
+	stateConfirmPlan
+	statePlanning
+	stateDone
+	stateFailed
+)
+
+// prompts are the keys of the message shown in each state
+var prompts = map[state]string{
+	stateConfirmValidate: "run.confirm_validate",
+	stateValidating:      "run.validating",
+	stateConfirmPlan:     "run.confirm_plan",
+	statePlanning:        "run.planning",
+	stateDone:            "run.done",
+	stateFailed:          "run.failed",
+}
+
+type TerraformColumnModel struct {
+	runner terraform.Runner
+	// commit is set for projects using git which were clean when the pipeline started,
+	// the formatted files and the lock file of the providers are committed after each run
+	commit bool
+	// environments are the environments of the project, the selected one is planned
+	environments []string
+	environment  int
+	state        state
+	output       []string
+	viewport     viewport.Model
+	lines        chan string
+	done         chan error
+	keys         helpers.KeyMap
+	models.ColumnModel
+}
+
+func NewTerraformColumnModel(focused bool) *TerraformColumnModel {
+	m := &TerraformColumnModel{
+		keys:     helpers.Keys,
+		viewport: viewport.New(0, 0),
+	}
+	m.SetFocused(focused)
+	m.SetSize(styles.DefaultLayout.RightWidth, styles.DefaultLayout.RightHeight)
+	return m
+}
+
+// SetSize fits the output viewport in the column
+func (m *TerraformColumnModel) SetSize(width, height int) {
+	m.ColumnModel.SetSize(width, height)
+	m.viewport.Width = max(width-viewportHorizontalMargin, 1)
+	m.viewport.Height = max(height-viewportVerticalMargin, 1)
+}
+
+func (m *TerraformColumnModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *TerraformColumnModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			if !m.running() {
+				return m, messages.SwitchColumn("select_column")
+			}
+		case key.Matches(msg, m.keys.Enter):
+			switch m.state {
+			case stateConfirmValidate:
+				m.state = stateValidating
+				return m, m.run(terraform.ValidateSteps)
+			case stateConfirmPlan:
+				m.state = statePlanning
+				return m, m.run(m.planSteps())
+			}
+		case key.Matches(msg, m.keys.Tab) && m.state == stateConfirmPlan && len(m.environments) > 1:
+			m.environment = (m.environment + 1) % len(m.environments)
+			return m, nil
+		case key.Matches(msg, m.keys.ShiftTab) && m.state == stateConfirmPlan && len(m.environments) > 1:
+			m.environment = (m.environment + len(m.environments) - 1) % len(m.environments)
+			return m, nil
+		}
+	case messages.TerraformOutputMsg:
+		m.output = append(m.output, msg.Line)
+		m.viewport.SetContent(strings.Join(m.output, "\n"))
+		m.viewport.GotoBottom()
+		return m, m.waitForOutput()
+	case messages.TerraformDoneMsg:
+		switch {
+		case msg.Err != nil:
+			m.output = append(m.output, msg.Err.Error())
+			m.viewport.SetContent(strings.Join(m.output, "\n"))
+			m.viewport.GotoBottom()
+			m.state = stateFailed
+		case m.state == stateValidating:
+			m.state = stateConfirmPlan
+		default:
+			m.state = stateDone
+		}
+		return m, nil
+	}
+
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+func (m *TerraformColumnModel) View() string {
+	if !m.GetFocused() {
+		return m.Render(styles.SetupColumnStyleBlured, "", 0, 0)
+	}
+
+	prompt := i18n.T(prompts[m.state])
+	if m.state == stateConfirmPlan && len(m.environments) > 0 {
+		prompt = m.environmentsView() + "\n" + prompt
+	}
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		styles.GetFocusedTitle(i18n.T("run.title", m.runner.Dir), true),
+		"",
+		m.viewport.View(),
+		"",
+		prompt,
+	)
+
+	return m.Render(styles.SetupColumnStyleFocused, content, 0, 0)
+}
+
+// Start resets the column for the project in dir
+func (m *TerraformColumnModel) Start(binary, dir string) {
+	m.runner = terraform.NewRunner(binary, dir)
+	m.commit, m.environments, m.environment = false, nil, 0
+	if manifest, err := project.ReadManifest(dir); err == nil {
+		m.environments = manifest.Environments
+		if manifest.Git {
+			m.commit, _ = vcs.Clean(dir)
+		}
+	}
+	m.state = stateConfirmValidate
+	m.output = nil
+	m.viewport.SetContent("")
+}
+
+func (m *TerraformColumnModel) running() bool {
+	return m.state == stateValidating || m.state == statePlanning
+}
+
+// planSteps plans the selected environment in projects using environments
+func (m *TerraformColumnModel) planSteps() []terraform.Step {
+	if len(m.environments) == 0 {
+		return terraform.PlanSteps
+	}
+	return terraform.EnvironmentPlanSteps(templates.EnvironmentArgs(m.environments[m.environment]))
+}
+
+// environmentsView shows the environments of the project with the planned one selected
+func (m *TerraformColumnModel) environmentsView() string {
+	names := make([]string, 0, len(m.environments))
+	for i, env := range m.environments {
+		if i == m.environment {
+			names = append(names, styles.SelectedChoiceStyle.Render(env))
+		} else {
+			names = append(names, env)
+		}
+	}
+	return i18n.T("run.environment", strings.Join(names, " · "))
+}
+
+// run starts the steps in background, their output is read by waitForOutput
+func (m *TerraformColumnModel) run(steps []terraform.Step) tea.Cmd {
+	m.lines = make(chan string)
+	m.done = make(chan error, 1)
+
+	go func(runner terraform.Runner, commit bool, lines chan string, done chan error) {
+		err := runner.RunSteps(steps, lines)
+		if err == nil && commit {
+			// fmt and init change the project, it stays clean for the next operations
+			lines <- "$ git commit -m " + strconv.Quote(commitMessage)
+			err = vcs.Commit(runner.Dir, commitMessage)
+		}
+		done <- err
+		close(lines)
+	}(m.runner, m.commit, m.lines, m.done)
+
+	return m.waitForOutput()
+}
+
+// waitForOutput returns the next line of output, or the result once the steps finish
+func (m *TerraformColumnModel) waitForOutput() tea.Cmd {
+	lines, done := m.lines, m.done
+	return func() tea.Msg {
+		if line, ok := <-lines; ok {
+			return messages.TerraformOutputMsg{Line: line}
+		}
+		return messages.TerraformDoneMsg{Err: <-done}
+	}
+}
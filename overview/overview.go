@@ -0,0 +1,333 @@
+package overview
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/xsevy/terrapi/drift"
+	"github.com/xsevy/terrapi/helpers/hcl"
+	"github.com/xsevy/terrapi/project"
+)
+
+const schemaFileName = "schema.graphql"
+
+var (
+	localRegex      = regexp.MustCompile(`^local\.([a-zA-Z0-9_-]+)$`)
+	moduleRefRegex  = regexp.MustCompile(`^module\.([a-zA-Z0-9_-]+)\.`)
+	dataSourceRegex = regexp.MustCompile(`^aws_appsync_datasource\.([a-zA-Z0-9_-]+)\.`)
+	fileRegex       = regexp.MustCompile(`file\("([^"]+)"\)`)
+)
+
+// generatedDirs are built by terraform from the sources of a module, they are not listed in its files
+var generatedDirs = map[string]bool{"lambda_layer_files": true, ".terraform": true}
+
+// API describes the AppSync API of a project
+type API struct {
+	Name     string
+	AuthMode string
+	Region   string
+	// Backend is the S3 bucket and key the state is stored in
+	Backend string
+	Files   []string
+}
+
+// DataSource is an AppSync data source, Runtime is set for lambda data sources generated by terrapi
+type DataSource struct {
+	Name    string
+	Type    string
+	Runtime string
+	Files   []string
+}
+
+// Resolver attaches a data source to a field of the schema
+type Resolver struct {
+	Type       string
+	Field      string
+	DataSource string
+	Files      []string
+}
+
+// Schema counts the types and the root fields of the GraphQL schema
+type Schema struct {
+	Types         int
+	Queries       int
+	Mutations     int
+	Subscriptions int
+	Files         []string
+}
+
+// Overview is what a terrapi project contains, file paths are slash separated and relative to Dir
+type Overview struct {
+	Dir          string
+	Environments []string
+	API          API
+	DataSources  []DataSource
+	Resolvers    []Resolver
+	Schema       Schema
+}
+
+// block is a top level block with the file defining it
+type block struct {
+	hcl.Block
+	file string
+}
+
+// Read describes the project in dir, which must be the root of a terrapi project
+func Read(dir string) (*Overview, error) {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := readBlocks(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	locals := map[string]string{}
+	modules := map[string]block{}
+	for _, b := range blocks {
+		switch b.Type {
+		case "locals":
+			for name, expr := range b.Attributes() {
+				locals[name] = expr
+			}
+		case "module":
+			modules[b.Label(0)] = b
+		}
+	}
+	evaluate := func(expr string) string {
+		if match := localRegex.FindStringSubmatch(expr); match != nil {
+			expr = locals[match[1]]
+		}
+		value, _ := literal(expr)
+		return value
+	}
+
+	o := &Overview{
+		Dir:          dir,
+		Environments: manifest.Environments,
+		API: API{
+			Name:   manifest.Name,
+			Region: evaluate("local.aws_region"),
+		},
+	}
+	if o.API.Region == "" {
+		o.API.Region = manifest.Region
+	}
+
+	for _, b := range blocks {
+		switch {
+		case b.Type == "terraform":
+			for _, child := range b.Children() {
+				if child.Type == "backend" {
+					o.API.Backend = backend(child, evaluate)
+					o.API.Files = appendFile(o.API.Files, b.file)
+				}
+			}
+		case b.Type == "resource" && b.Label(0) == "aws_appsync_graphql_api":
+			o.API.AuthMode = attribute(b.Block, "authentication_type", evaluate)
+			o.API.Files = appendFile(o.API.Files, b.file)
+		case b.Type == "resource" && b.Label(0) == "aws_appsync_datasource":
+			o.DataSources = append(o.DataSources, o.dataSource(b, modules, evaluate))
+		case b.Type == "resource" && b.Label(0) == "aws_appsync_resolver":
+			o.Resolvers = append(o.Resolvers, resolver(b, evaluate))
+		}
+	}
+
+	sort.Slice(o.Resolvers, func(i, j int) bool {
+		if o.Resolvers[i].Type != o.Resolvers[j].Type {
+			return o.Resolvers[i].Type < o.Resolvers[j].Type
+		}
+		return o.Resolvers[i].Field < o.Resolvers[j].Field
+	})
+
+	schema, err := os.ReadFile(filepath.Join(dir, schemaFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		o.Schema = schemaStats(string(schema))
+		o.Schema.Files = []string{schemaFileName}
+	}
+
+	return o, nil
+}
+
+// dataSource describes an aws_appsync_datasource, the files of the module of a lambda data source are included
+func (o *Overview) dataSource(b block, modules map[string]block, evaluate func(string) string) DataSource {
+	d := DataSource{
+		Name:  strings.TrimSuffix(b.Label(1), "_data_source"),
+		Type:  attribute(b.Block, "type", evaluate),
+		Files: []string{b.file},
+	}
+
+	for _, child := range b.Children() {
+		if child.Type != "lambda_config" {
+			continue
+		}
+		arn, _, _ := child.Attribute("function_arn")
+		match := moduleRefRegex.FindStringSubmatch(arn)
+		if match == nil {
+			continue
+		}
+		module, ok := modules[match[1]]
+		if !ok {
+			continue
+		}
+		source, _ := literal(attribute(module.Block, "source", nil))
+		if !strings.HasPrefix(source, "./") {
+			continue
+		}
+
+		moduleDir := filepath.Join(o.Dir, source)
+		d.Runtime = moduleRuntime(moduleDir)
+		d.Files = append(d.Files, moduleFiles(o.Dir, moduleDir)...)
+	}
+
+	return d
+}
+
+// resolver describes an aws_appsync_resolver with the files of its mapping templates or code
+func resolver(b block, evaluate func(string) string) Resolver {
+	r := Resolver{
+		Type:  attribute(b.Block, "type", evaluate),
+		Field: attribute(b.Block, "field", evaluate),
+		Files: []string{b.file},
+	}
+
+	dataSource, _, _ := b.Attribute("data_source")
+	if match := dataSourceRegex.FindStringSubmatch(dataSource); match != nil {
+		r.DataSource = strings.TrimSuffix(match[1], "_data_source")
+	}
+
+	for _, name := range []string{"code", "request_template", "response_template"} {
+		expr, _, _ := b.Attribute(name)
+		if match := fileRegex.FindStringSubmatch(expr); match != nil {
+			r.Files = append(r.Files, filepath.ToSlash(filepath.Clean(match[1])))
+		}
+	}
+
+	return r
+}
+
+// backend describes where the state is stored
+func backend(b hcl.Block, evaluate func(string) string) string {
+	bucket := attribute(b, "bucket", evaluate)
+	key := attribute(b, "key", evaluate)
+	if key == "" {
+		key = "<environment key>"
+	}
+	return fmt.Sprintf("%s %s/%s", b.Label(0), bucket, key)
+}
+
+// schemaStats counts the types of a schema apart from the root types, and the fields of the root types
+func schemaStats(schema string) Schema {
+	var s Schema
+	for name := range drift.SchemaFields(schema) {
+		typeName, _, isField := strings.Cut(name, ".")
+		switch {
+		case !isField && typeName != "Query" && typeName != "Mutation" && typeName != "Subscription":
+			s.Types++
+		case isField && typeName == "Query":
+			s.Queries++
+		case isField && typeName == "Mutation":
+			s.Mutations++
+		case isField && typeName == "Subscription":
+			s.Subscriptions++
+		}
+	}
+	return s
+}
+
+// moduleRuntime returns the lambda runtime set in the locals of a module generated by terrapi
+func moduleRuntime(dir string) string {
+	blocks, err := readBlocks(dir)
+	if err != nil {
+		return ""
+	}
+	for _, b := range blocks {
+		if b.Type != "locals" {
+			continue
+		}
+		if runtime, ok := literal(attribute(b.Block, "lambda_runtime", nil)); ok {
+			return runtime
+		}
+	}
+	return ""
+}
+
+// moduleFiles lists the source files of a module relative to root, the files generated by terraform are skipped
+func moduleFiles(root, dir string) []string {
+	var files []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if generatedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".zip" {
+			return nil
+		}
+		if name, err := filepath.Rel(root, path); err == nil {
+			files = append(files, filepath.ToSlash(name))
+		}
+		return nil
+	})
+	return files
+}
+
+// readBlocks parses the terraform files of dir
+func readBlocks(dir string) ([]block, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var blocks []block
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range hcl.Parse(strings.Split(string(content), "\n")) {
+			blocks = append(blocks, block{Block: b, file: filepath.Base(path)})
+		}
+	}
+	return blocks, nil
+}
+
+// attribute returns the value of an attribute, evaluated when evaluate is given
+func attribute(b hcl.Block, name string, evaluate func(string) string) string {
+	expr, _, _ := b.Attribute(name)
+	if evaluate == nil {
+		return expr
+	}
+	return evaluate(expr)
+}
+
+// literal returns the value of a quoted string without interpolations
+func literal(expr string) (string, bool) {
+	if len(expr) < 2 || !strings.HasPrefix(expr, `"`) || !strings.HasSuffix(expr, `"`) || strings.Contains(expr, "${") {
+		return "", false
+	}
+	return expr[1 : len(expr)-1], true
+}
+
+func appendFile(files []string, file string) []string {
+	if slices.Contains(files, file) {
+		return files
+	}
+	return append(files, file)
+}
@@ -0,0 +1,114 @@
+package overview
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/templates"
+)
+
+func TestRead(t *testing.T) {
+	dest := t.TempDir()
+	api := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		BackendKey:               "users/terraform.tfstate",
+		AuthorizerLambdaFunction: "authorizer",
+	}
+	if err := templates.CreateResources(api.ID, dest, api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+
+	dataSource := &messages.CreateResourceMsg{
+		ID:            helpers.ResourceIDs.CreateAppSyncDataSource,
+		ProjectName:   "posts",
+		LambdaRuntime: "python3.11",
+	}
+	if err := templates.CreateResources(dataSource.ID, dir, dataSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolvers := `
+resource "aws_appsync_resolver" "query_posts" {
+  api_id            = aws_appsync_graphql_api.appsync.id
+  type              = "Query"
+  field             = "posts"
+  data_source       = aws_appsync_datasource.posts_data_source.name
+  request_template  = file("resolvers/Query.posts.request.vtl")
+  response_template = file("resolvers/Query.posts.response.vtl")
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "resolvers.tf"), []byte(resolvers), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := "type Post {\n  id: ID!\n}\n\ntype Query {\n  posts: [Post]\n  post(id: ID!): Post\n}\n\ntype Mutation {\n  addPost: Post\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "schema.graphql"), []byte(schema), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	o, err := Read(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedAPI := API{
+		Name:     "users",
+		AuthMode: "AWS_LAMBDA",
+		Region:   "eu-central-1",
+		Backend:  "s3 bucket/users/terraform.tfstate",
+		Files:    []string{"appsync.tf", "backend.tf"},
+	}
+	if !reflect.DeepEqual(o.API, expectedAPI) {
+		t.Errorf("expected API %+v, got %+v", expectedAPI, o.API)
+	}
+
+	if len(o.DataSources) != 1 {
+		t.Fatalf("expected 1 data source, got %+v", o.DataSources)
+	}
+	posts := o.DataSources[0]
+	if posts.Name != "posts" || posts.Type != "AWS_LAMBDA" || posts.Runtime != "python3.11" {
+		t.Errorf("unexpected data source %+v", posts)
+	}
+	for _, file := range []string{"datasources.tf", "posts/lambda.tf", "posts/lambda/index.py"} {
+		if !contains(posts.Files, file) {
+			t.Errorf("expected %s in the data source files %v", file, posts.Files)
+		}
+	}
+
+	expectedResolvers := []Resolver{{
+		Type:       "Query",
+		Field:      "posts",
+		DataSource: "posts",
+		Files:      []string{"resolvers.tf", "resolvers/Query.posts.request.vtl", "resolvers/Query.posts.response.vtl"},
+	}}
+	if !reflect.DeepEqual(o.Resolvers, expectedResolvers) {
+		t.Errorf("expected resolvers %+v, got %+v", expectedResolvers, o.Resolvers)
+	}
+
+	expectedSchema := Schema{Types: 1, Queries: 2, Mutations: 1, Files: []string{"schema.graphql"}}
+	if !reflect.DeepEqual(o.Schema, expectedSchema) {
+		t.Errorf("expected schema %+v, got %+v", expectedSchema, o.Schema)
+	}
+}
+
+func TestReadOutsideProject(t *testing.T) {
+	if _, err := Read(t.TempDir()); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
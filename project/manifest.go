@@ -0,0 +1,80 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ManifestFileName is the file marking the root of a terrapi project
+const ManifestFileName = ".terrapi"
+
+// Manifest describes a terrapi project, it is stored as JSON in the ManifestFileName file
+type Manifest struct {
+	Name         string   `json:"name"`
+	Region       string   `json:"region,omitempty"`
+	BackendKey   string   `json:"backend_key,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+	// APIID is set for projects created by importing an existing API
+	APIID string `json:"api_id,omitempty"`
+	// Git is set for projects created with a git repository, every terrapi operation is committed
+	Git bool `json:"git,omitempty"`
+	// Templates lists the templates rendered into the project, in the order they were applied
+	Templates []Template `json:"templates,omitempty"`
+}
+
+// Template records which version of a template generated which files, so the project can be upgraded
+type Template struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+	// Values are the replacements the template was rendered with
+	Values map[string]string `json:"values,omitempty"`
+	// Files maps the generated files, relative to the project, to the sha256 of the generated content
+	Files map[string]string `json:"files,omitempty"`
+}
+
+// ReadManifest reads the manifest of the project in dir.
+// Projects created before the manifest was introduced have an empty file, which gives an empty manifest.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if len(data) == 0 {
+		return m, nil
+	}
+
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Write saves the manifest in the project in dir
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ManifestFileName), append(data, '\n'), 0644)
+}
+
+// HasEnvironments reports whether the project uses the environments layout
+func (m *Manifest) HasEnvironments() bool {
+	return len(m.Environments) > 0
+}
+
+// HasEnvironment reports whether the project already has the environment
+func (m *Manifest) HasEnvironment(env string) bool {
+	for _, e := range m.Environments {
+		if e == env {
+			return true
+		}
+	}
+
+	return false
+}
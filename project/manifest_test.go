@@ -0,0 +1,135 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	tcs := []struct {
+		name        string
+		content     string
+		expected    *Manifest
+		expectError bool
+	}{
+		{
+			name:        "Empty manifest",
+			content:     "",
+			expected:    &Manifest{},
+			expectError: false,
+		},
+		{
+			name:    "Manifest with environments",
+			content: `{"name": "users", "region": "eu-central-1", "environments": ["dev", "prod"]}`,
+			expected: &Manifest{
+				Name:         "users",
+				Region:       "eu-central-1",
+				Environments: []string{"dev", "prod"},
+			},
+			expectError: false,
+		},
+		{
+			name:        "Invalid manifest",
+			content:     "name: users",
+			expected:    nil,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ManifestFileName), []byte(tc.content), 0644); err != nil {
+				t.Fatalf("unable to create manifest: %v", err)
+			}
+
+			m, err := ReadManifest(dir)
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(m, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, m)
+			}
+		})
+	}
+}
+
+func TestManifestWrite(t *testing.T) {
+	dir := t.TempDir()
+	m := &Manifest{Name: "users", Environments: []string{"dev"}}
+
+	if err := m.Write(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(m, read) {
+		t.Errorf("expected %+v, got %+v", m, read)
+	}
+
+	if !read.HasEnvironments() || !read.HasEnvironment("dev") || read.HasEnvironment("prod") {
+		t.Errorf("unexpected environments %v", read.Environments)
+	}
+}
+
+func TestReadMissingManifest(t *testing.T) {
+	if _, err := ReadManifest(t.TempDir()); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestFindRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := (&Manifest{Name: "users"}).Write(root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nested := filepath.Join(root, "posts", "lambda")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	outside := t.TempDir()
+
+	tcs := []struct {
+		name        string
+		dir         string
+		expected    string
+		expectError bool
+	}{
+		{name: "Project root", dir: root, expected: root},
+		{name: "Nested directory", dir: nested, expected: root},
+		{name: "Outside of a project", dir: outside, expectError: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			found, err := FindRoot(tc.dir)
+			if tc.expectError {
+				if !errors.Is(err, ErrNoProject) {
+					t.Errorf("expected ErrNoProject, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, found)
+			}
+		})
+	}
+}
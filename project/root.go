@@ -0,0 +1,41 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoProject is returned when no ManifestFileName is found in a directory or its parents
+var ErrNoProject = errors.New("not in a terrapi project")
+
+// FindRoot returns the project root containing dir, the nearest directory holding a ManifestFileName
+func FindRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for current := abs; ; current = filepath.Dir(current) {
+		info, err := os.Stat(filepath.Join(current, ManifestFileName))
+		if err == nil && !info.IsDir() {
+			return current, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+
+		if filepath.Dir(current) == current {
+			return "", fmt.Errorf("%w: no %s found in %s or its parent directories", ErrNoProject, ManifestFileName, abs)
+		}
+	}
+}
+
+// FindRootOrDir returns the project root containing dir, or dir itself outside of a project
+func FindRootOrDir(dir string) string {
+	if root, err := FindRoot(dir); err == nil {
+		return root
+	}
+	return dir
+}
@@ -0,0 +1,50 @@
+package styles
+
+// sizes of the columns, without their borders
+const (
+	selectColumnWidth   = 25
+	minRightColumnWidth = 50
+	// stackedSelectHeight fits the choices of the select column
+	stackedSelectHeight = 3
+	columnBorderSize    = 2
+)
+
+// Layout is the size of the content of the columns, inside their borders
+type Layout struct {
+	// Stacked puts the right column under the select column, for narrow terminals
+	Stacked      bool
+	SelectWidth  int
+	SelectHeight int
+	RightWidth   int
+	RightHeight  int
+}
+
+// DefaultLayout is used until the size of the terminal is known
+var DefaultLayout = Layout{
+	SelectWidth:  selectColumnWidth,
+	SelectHeight: 28,
+	RightWidth:   minRightColumnWidth,
+	RightHeight:  28,
+}
+
+// NewLayout fits the columns in a terminal area of width by height. The select column keeps its width
+// and the right column takes the rest, unless it would be narrower than its minimum width,
+// then the columns are stacked.
+func NewLayout(width, height int) Layout {
+	if width < selectColumnWidth+minRightColumnWidth+2*columnBorderSize {
+		return Layout{
+			Stacked:      true,
+			SelectWidth:  max(width-columnBorderSize, 1),
+			SelectHeight: stackedSelectHeight,
+			RightWidth:   max(width-columnBorderSize, 1),
+			RightHeight:  max(height-stackedSelectHeight-2*columnBorderSize, 1),
+		}
+	}
+
+	return Layout{
+		SelectWidth:  selectColumnWidth,
+		SelectHeight: max(height-columnBorderSize, 1),
+		RightWidth:   width - selectColumnWidth - 2*columnBorderSize,
+		RightHeight:  max(height-columnBorderSize, 1),
+	}
+}
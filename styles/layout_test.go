@@ -0,0 +1,48 @@
+package styles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewLayout(t *testing.T) {
+	tcs := []struct {
+		name     string
+		width    int
+		height   int
+		expected Layout
+	}{
+		{
+			name:     "wide terminal",
+			width:    120,
+			height:   40,
+			expected: Layout{SelectWidth: 25, SelectHeight: 38, RightWidth: 91, RightHeight: 38},
+		},
+		{
+			name:     "smallest side by side layout",
+			width:    79,
+			height:   30,
+			expected: Layout{SelectWidth: 25, SelectHeight: 28, RightWidth: 50, RightHeight: 28},
+		},
+		{
+			name:     "narrow terminal",
+			width:    78,
+			height:   30,
+			expected: Layout{Stacked: true, SelectWidth: 76, SelectHeight: 3, RightWidth: 76, RightHeight: 23},
+		},
+		{
+			name:     "tiny terminal",
+			width:    1,
+			height:   2,
+			expected: Layout{Stacked: true, SelectWidth: 1, SelectHeight: 3, RightWidth: 1, RightHeight: 1},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if layout := NewLayout(tc.width, tc.height); !reflect.DeepEqual(layout, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, layout)
+			}
+		})
+	}
+}
@@ -5,24 +5,75 @@ import (
 	"github.com/xsevy/terrapi/helpers"
 )
 
+// the styles depending on the colors are set by SetTheme
 var (
-	commonColumnStyle  = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).Height(28).PaddingRight(1).PaddingLeft(1)
-	bluredColumnStyle  = commonColumnStyle.Copy().BorderForeground(lipgloss.Color(helpers.Colors.Grey))
-	focusedColumnStyle = commonColumnStyle.Copy().BorderForeground(lipgloss.Color(helpers.Colors.Purple))
+	SelectColumnStyleFocused lipgloss.Style
+	SelectColumnStyleBlured  lipgloss.Style
 
-	SelectColumnStyleFocused = lipgloss.NewStyle().Width(25).PaddingLeft(2).Inherit(focusedColumnStyle)
-	SelectColumnStyleBlured  = lipgloss.NewStyle().Width(25).PaddingLeft(2).Inherit(bluredColumnStyle)
-
-	SetupColumnStyleFocused = lipgloss.NewStyle().Width(50).PaddingLeft(2).Inherit(focusedColumnStyle)
-	SetupColumnStyleBlured  = lipgloss.NewStyle().Width(50).PaddingLeft(2).Inherit(bluredColumnStyle)
+	SetupColumnStyleFocused lipgloss.Style
+	SetupColumnStyleBlured  lipgloss.Style
 
 	ChoiceStyle         = lipgloss.NewStyle()
-	SelectedChoiceStyle = lipgloss.NewStyle().Background(lipgloss.Color(helpers.Colors.Purple))
-	DisabledChoiceStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(helpers.Colors.Grey))
+	SelectedChoiceStyle lipgloss.Style
+	DisabledChoiceStyle lipgloss.Style
+
+	FieldErrorStyle lipgloss.Style
+
+	// AccentStyle highlights text, like the filter of a list
+	AccentStyle lipgloss.Style
+
+	ButtonStyleFocused  lipgloss.Style
+	ButtonStyleBlured   lipgloss.Style
+	ButtonStyleDisabled lipgloss.Style
 
 	focusedTitle = lipgloss.NewStyle().Underline(true)
 )
 
+func init() {
+	SetTheme(helpers.Colors)
+}
+
+// SetTheme makes theme the current one and builds the styles with its colors. Themes without
+// an accent color reverse the selection and give the focused column a thick border instead.
+func SetTheme(theme helpers.Theme) {
+	helpers.Colors = theme
+
+	// the columns are sized by their model, see models.ColumnModel.Render
+	commonColumnStyle := lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).PaddingRight(1).PaddingLeft(1)
+	bluredColumnStyle := commonColumnStyle.Copy().BorderForeground(lipgloss.Color(theme.Muted))
+	focusedColumnStyle := commonColumnStyle.Copy().BorderForeground(lipgloss.Color(theme.Accent))
+
+	SelectedChoiceStyle = lipgloss.NewStyle().
+		Background(lipgloss.Color(theme.Accent)).
+		Foreground(lipgloss.Color(theme.AccentText))
+	ButtonStyleFocused = buttonStyle().
+		Background(lipgloss.Color(theme.Accent)).
+		Foreground(lipgloss.Color(theme.AccentText))
+	ButtonStyleBlured = buttonStyle().Background(lipgloss.Color(theme.Muted))
+	ButtonStyleDisabled = buttonStyle().Foreground(lipgloss.Color(theme.Muted))
+
+	if theme.Accent == "" {
+		focusedColumnStyle = focusedColumnStyle.BorderStyle(lipgloss.ThickBorder())
+		SelectedChoiceStyle = SelectedChoiceStyle.Reverse(true)
+		ButtonStyleFocused = ButtonStyleFocused.Reverse(true)
+		ButtonStyleDisabled = ButtonStyleDisabled.Faint(true)
+	}
+
+	SelectColumnStyleFocused = lipgloss.NewStyle().PaddingLeft(2).Inherit(focusedColumnStyle)
+	SelectColumnStyleBlured = lipgloss.NewStyle().PaddingLeft(2).Inherit(bluredColumnStyle)
+
+	SetupColumnStyleFocused = lipgloss.NewStyle().PaddingLeft(2).Inherit(focusedColumnStyle)
+	SetupColumnStyleBlured = lipgloss.NewStyle().PaddingLeft(2).Inherit(bluredColumnStyle)
+
+	DisabledChoiceStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Muted))
+	FieldErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Error))
+	AccentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Accent))
+}
+
+func buttonStyle() lipgloss.Style {
+	return lipgloss.NewStyle().PaddingTop(1).PaddingBottom(1).PaddingRight(4).PaddingLeft(4)
+}
+
 func GetFocusedTitle(title string, focused bool) string {
 	if focused {
 		return focusedTitle.Render(title)
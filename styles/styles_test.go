@@ -0,0 +1,27 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/xsevy/terrapi/helpers"
+)
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(helpers.Themes[helpers.DefaultTheme])
+
+	SetTheme(helpers.Themes[helpers.HighContrastTheme])
+	if helpers.Colors != helpers.Themes[helpers.HighContrastTheme] {
+		t.Errorf("expected the theme to become the current one, got %+v", helpers.Colors)
+	}
+	if SelectedChoiceStyle.GetReverse() {
+		t.Error("expected the selection of a colored theme not to be reversed")
+	}
+
+	SetTheme(helpers.Themes[helpers.NoColorTheme])
+	if !SelectedChoiceStyle.GetReverse() || !ButtonStyleFocused.GetReverse() {
+		t.Error("expected the selection to be reversed without colors")
+	}
+	if SetupColumnStyleFocused.GetBorderStyle() == SetupColumnStyleBlured.GetBorderStyle() {
+		t.Error("expected the focused column to have another border without colors")
+	}
+}
@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/helpers/hcl"
+	"github.com/xsevy/terrapi/project"
+)
+
+// adoptTemplates records the templates of a project generated before the manifest recorded them.
+// The API and its data source modules are recorded with version 0 and the values read from their files,
+// without hashes or base copies, so Upgrade merges the current templates with the project as it is.
+func adoptTemplates(dir string, manifest *project.Manifest) error {
+	if manifest.Name == "" {
+		manifest.Name = filepath.Base(dir)
+	}
+
+	backend := readBlock(filepath.Join(dir, "backend.tf"), "terraform").Children()
+	var s3 hcl.Block
+	for _, b := range backend {
+		if b.Type == "backend" && b.Label(0) == "s3" {
+			s3 = b
+		}
+	}
+	authorizer := readBlock(filepath.Join(dir, "lambda.tf"), "data", "aws_lambda_function", "authorizer")
+
+	region := manifest.Region
+	if region == "" {
+		region = stringAttribute(s3, "region")
+	}
+	values := map[string]string{
+		"ProjectName":               manifest.Name,
+		"AWSRegion":                 region,
+		"BackendBucket":             stringAttribute(s3, "bucket"),
+		"BackendKey":                stringAttribute(s3, "key"),
+		"BackendLockTable":          stringAttribute(s3, "dynamodb_table"),
+		"BackendWorkspaceKeyPrefix": stringAttribute(s3, "workspace_key_prefix"),
+		"AuthorizerLambdaFunction":  stringAttribute(authorizer, "function_name"),
+	}
+
+	id := helpers.ResourceIDs.CreateAppSyncAPI
+	if manifest.APIID != "" {
+		id = helpers.ResourceIDs.ImportAppSyncAPI
+	}
+	manifest.Templates = append(manifest.Templates, project.Template{ID: id, Values: nonEmpty(values)})
+
+	dataSources, err := DataSourceNames(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range dataSources {
+		locals := readBlock(filepath.Join(dir, name, "locals.tf"), "locals")
+		manifest.Templates = append(manifest.Templates, project.Template{
+			ID: helpers.ResourceIDs.CreateAppSyncDataSource,
+			Values: nonEmpty(map[string]string{
+				"ProjectName":   name,
+				"LambdaRuntime": stringAttribute(locals, "lambda_runtime"),
+			}),
+		})
+	}
+
+	return nil
+}
+
+// readBlock returns the first top level block of the file with the type and labels,
+// or an empty block when the file or the block does not exist
+func readBlock(path string, blockType string, labels ...string) hcl.Block {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return hcl.Block{}
+	}
+
+	for _, b := range hcl.Parse(strings.Split(string(content), "\n")) {
+		if b.Type != blockType || len(b.Labels) < len(labels) {
+			continue
+		}
+		matches := true
+		for i, label := range labels {
+			matches = matches && b.Label(i) == label
+		}
+		if matches {
+			return b
+		}
+	}
+	return hcl.Block{}
+}
+
+// stringAttribute returns the value of a string literal attribute, or an empty string
+func stringAttribute(b hcl.Block, name string) string {
+	expr, _, ok := b.Attribute(name)
+	if !ok {
+		return ""
+	}
+	value, err := strconv.Unquote(expr)
+	if err != nil || strings.Contains(value, "${") {
+		return ""
+	}
+	return value
+}
+
+// nonEmpty drops the empty values, like templateValues does
+func nonEmpty(values map[string]string) map[string]string {
+	for name, value := range values {
+		if value == "" {
+			delete(values, name)
+		}
+	}
+	return values
+}
@@ -0,0 +1,250 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+const (
+	importTemplatesDir    = "source/import_app_sync_api"
+	importTemplatesSuffix = ".tmpl"
+	schemaFileName        = "schema.graphql"
+	resolversDirName      = "resolvers"
+	functionsDirName      = "functions"
+)
+
+// files reused from the create API template, their content does not depend on the imported API
+var importedAPIFiles = []string{".gitignore", "README.md", "backend.tf", "locals.tf", "resolvers/.gitkeep"}
+
+var identifierRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+type importedDataSource struct {
+	aws.DataSource
+	Resource string
+}
+
+type importedFunction struct {
+	aws.Function
+	Resource     string
+	DataSource   string
+	CodeFile     string
+	RequestFile  string
+	ResponseFile string
+}
+
+type importedResolver struct {
+	aws.Resolver
+	Resource          string
+	DataSource        string
+	CodeFile          string
+	RequestFile       string
+	ResponseFile      string
+	FunctionResources []string
+}
+
+// importData is passed to the import templates
+type importData struct {
+	API         aws.GraphqlAPI
+	DataSources []importedDataSource
+	Functions   []importedFunction
+	Resolvers   []importedResolver
+	// code of resolvers and functions by path in the project
+	code map[string]string
+}
+
+// importAppSyncApi creates a project managing an existing AppSync API
+func importAppSyncApi(src, dest string, replacements *messages.CreateResourceMsg) error {
+	if err := checkRequiredFields(replacements.ProjectName); err != nil {
+		return err
+	}
+	if replacements.AppSyncExport == nil {
+		return fmt.Errorf("missing export of the api to import")
+	}
+
+	dir := filepath.Join(dest, replacements.ProjectName)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("directory %s already exists", dir)
+	}
+
+	record := creationRecord{}
+	if err := createDirectory(dir); err != nil {
+		return err
+	}
+	record.add(dir)
+
+	if err := writeImportedProject(dir, replacements); err != nil {
+		record.rollback()
+		return err
+	}
+
+	return nil
+}
+
+func writeImportedProject(dir string, replacements *messages.CreateResourceMsg) error {
+	apiSrc := path.Join("source", "create_app_sync_api", "{{ProjectName}}")
+	for _, name := range importedAPIFiles {
+		dest := filepath.Join(dir, name)
+		if err := createDirectory(filepath.Dir(dest)); err != nil {
+			return err
+		}
+		if err := createFile(sourceFiles, path.Join(apiSrc, name), dest, replacements); err != nil {
+			return err
+		}
+	}
+
+	data := newImportData(replacements.AppSyncExport)
+
+	tmpl, err := template.New("import").
+		Funcs(template.FuncMap{"hcl": hclString}).
+		ParseFS(sourceFiles, path.Join(importTemplatesDir, "*"+importTemplatesSuffix))
+	if err != nil {
+		return fmt.Errorf("error creating template: %v", err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		if !strings.HasSuffix(t.Name(), importTemplatesSuffix) {
+			continue
+		}
+
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return fmt.Errorf("error executing template: %v", err)
+		}
+
+		content := strings.TrimSpace(b.String())
+		if content != "" {
+			content += "\n"
+		}
+		data.code[strings.TrimSuffix(t.Name(), importTemplatesSuffix)] = content
+	}
+	data.code[schemaFileName] = replacements.AppSyncExport.Schema
+
+	for name, content := range data.code {
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := createDirectory(filepath.Dir(dest)); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest, []byte(content), 0644); err != nil {
+			return fmt.Errorf("error creating file %s: %v", dest, err)
+		}
+	}
+
+	manifest := &project.Manifest{
+		Name:       replacements.ProjectName,
+		Region:     replacements.AWSRegion,
+		BackendKey: replacements.BackendKey,
+		APIID:      replacements.AppSyncExport.API.ID,
+		Git:        replacements.GitInit,
+	}
+	if err := recordTemplate(dir, manifest, helpers.ResourceIDs.ImportAppSyncAPI, replacements); err != nil {
+		return err
+	}
+	return manifest.Write(dir)
+}
+
+// newImportData names the terraform resources and places the resolver code in the project
+func newImportData(export *aws.APIExport) *importData {
+	data := &importData{
+		API:  export.API,
+		code: map[string]string{},
+	}
+	names := map[string]bool{}
+
+	dataSources := map[string]string{}
+	for _, ds := range export.DataSources {
+		resource := uniqueIdentifier(names, "datasource_"+ds.Name)
+		dataSources[ds.Name] = fmt.Sprintf("aws_appsync_datasource.%s.name", resource)
+		data.DataSources = append(data.DataSources, importedDataSource{DataSource: ds, Resource: resource})
+	}
+
+	functions := map[string]string{}
+	for _, f := range export.Functions {
+		resource := uniqueIdentifier(names, "function_"+f.Name)
+		functions[f.ID] = resource
+
+		imported := importedFunction{Function: f, Resource: resource, DataSource: dataSources[f.DataSourceName]}
+		if imported.DataSource == "" {
+			imported.DataSource = hclString(f.DataSourceName)
+		}
+		imported.CodeFile, imported.RequestFile, imported.ResponseFile = data.addCode(
+			path.Join(functionsDirName, resource), f.Code, f.RequestTemplate, f.ResponseTemplate,
+		)
+		data.Functions = append(data.Functions, imported)
+	}
+
+	for _, r := range export.Resolvers {
+		resource := uniqueIdentifier(names, r.TypeName+"_"+r.FieldName)
+
+		imported := importedResolver{Resolver: r, Resource: resource, DataSource: dataSources[r.DataSourceName]}
+		if imported.DataSource == "" && r.DataSourceName != "" {
+			imported.DataSource = hclString(r.DataSourceName)
+		}
+		for _, id := range r.Functions {
+			imported.FunctionResources = append(imported.FunctionResources, functions[id])
+		}
+		imported.CodeFile, imported.RequestFile, imported.ResponseFile = data.addCode(
+			path.Join(resolversDirName, r.TypeName+"."+r.FieldName), r.Code, r.RequestTemplate, r.ResponseTemplate,
+		)
+		data.Resolvers = append(data.Resolvers, imported)
+	}
+
+	return data
+}
+
+// addCode stores JS code or VTL mapping templates next to each other and returns their paths
+func (d *importData) addCode(base, code, request, response string) (string, string, string) {
+	var codeFile, requestFile, responseFile string
+
+	if code != "" {
+		codeFile = base + ".js"
+		d.code[codeFile] = code
+		return codeFile, "", ""
+	}
+
+	if request != "" {
+		requestFile = base + ".request.vtl"
+		d.code[requestFile] = request
+	}
+	if response != "" {
+		responseFile = base + ".response.vtl"
+		d.code[responseFile] = response
+	}
+
+	return codeFile, requestFile, responseFile
+}
+
+// uniqueIdentifier turns name into a terraform identifier not used yet
+func uniqueIdentifier(used map[string]bool, name string) string {
+	identifier := strings.Trim(identifierRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+
+	unique := identifier
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", identifier, i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+// hclString quotes s as a HCL string literal, without interpolation
+func hclString(s string) string {
+	quoted := strconv.Quote(s)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	quoted = strings.ReplaceAll(quoted, "%{", "%%{")
+
+	return quoted
+}
@@ -0,0 +1,174 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xsevy/terrapi/aws"
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+func TestImportAppSyncApi(t *testing.T) {
+	export := &aws.APIExport{
+		API: aws.GraphqlAPI{
+			ID:                           "abc123",
+			Name:                         "Users API",
+			AuthenticationType:           "AWS_LAMBDA",
+			AuthorizerURI:                "arn:aws:lambda:eu-central-1:123456789012:function:authorizer",
+			AuthorizerResultTTLInSeconds: 300,
+			Tags:                         map[string]string{"team": "users"},
+			AdditionalAuthenticationProviders: []aws.AuthenticationProvider{
+				{AuthenticationType: "AWS_IAM"},
+				{AuthenticationType: "AMAZON_COGNITO_USER_POOLS", UserPoolID: "eu-central-1_abc", UserPoolRegion: "eu-central-1"},
+			},
+		},
+		Schema: "type Query { user(id: ID!): User }",
+		DataSources: []aws.DataSource{
+			{Name: "users", Type: "AWS_LAMBDA", LambdaFunctionARN: "arn:aws:lambda:eu-central-1:123456789012:function:users"},
+			{Name: "table", Type: "AMAZON_DYNAMODB", TableName: "users", TableRegion: "eu-central-1"},
+			{
+				Name: "aurora", Type: "RELATIONAL_DATABASE", DBClusterIdentifier: "arn:aws:rds:eu-central-1:123456789012:cluster:users",
+				DatabaseName: "users", DatabaseRegion: "eu-central-1", SecretStoreARN: "arn:aws:secretsmanager:eu-central-1:123456789012:secret:users",
+			},
+			{Name: "search", Type: "AMAZON_OPENSEARCH_SERVICE", SearchEndpoint: "https://search-users.eu-central-1.es.amazonaws.com", SearchRegion: "eu-central-1"},
+			{Name: "legacy_search", Type: "AMAZON_ELASTICSEARCH", SearchEndpoint: "https://search-legacy.eu-central-1.es.amazonaws.com", SearchRegion: "eu-central-1"},
+		},
+		Functions: []aws.Function{
+			{ID: "fn1", Name: "getUser", DataSourceName: "table", Code: "export function request() {}", RuntimeName: "APPSYNC_JS", RuntimeVersion: "1.0.0"},
+		},
+		Resolvers: []aws.Resolver{
+			{TypeName: "Query", FieldName: "user", Kind: "PIPELINE", Code: "export function request() { return `${x}` }", RuntimeName: "APPSYNC_JS", RuntimeVersion: "1.0.0", Functions: []string{"fn1"}},
+			{TypeName: "Query", FieldName: "users", Kind: "UNIT", DataSourceName: "users", RequestTemplate: "{}", ResponseTemplate: "$util.toJson($ctx.result)"},
+		},
+	}
+	replacements := &messages.CreateResourceMsg{
+		ProjectName:      "users_api",
+		AWSRegion:        "eu-central-1",
+		BackendBucket:    "bucket",
+		BackendKey:       "users_api/terraform.tfstate",
+		BackendLockTable: "lock",
+		AppSyncExport:    export,
+	}
+	dest := t.TempDir()
+
+	if err := CreateResources(helpers.ResourceIDs.ImportAppSyncAPI, dest, replacements); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users_api")
+
+	expected := map[string][]string{
+		"schema.graphql": {export.Schema},
+		"appsync.tf": {
+			`name                = "Users API"`,
+			`authorizer_result_ttl_in_seconds = 300`,
+			`"team" = "users"`,
+			"additional_authentication_provider {\n    authentication_type = \"AWS_IAM\"\n  }",
+			`user_pool_id = "eu-central-1_abc"`,
+		},
+		"datasources.tf": {
+			`resource "aws_appsync_datasource" "datasource_users"`,
+			`table_name = "users"`,
+			`db_cluster_identifier = "arn:aws:rds:eu-central-1:123456789012:cluster:users"`,
+			`aws_secret_store_arn  = "arn:aws:secretsmanager:eu-central-1:123456789012:secret:users"`,
+			`database_name         = "users"`,
+			"opensearchservice_config {\n    endpoint = \"https://search-users.eu-central-1.es.amazonaws.com\"",
+			"elasticsearch_config {\n    endpoint = \"https://search-legacy.eu-central-1.es.amazonaws.com\"",
+		},
+		"functions.tf": {
+			`data_source = aws_appsync_datasource.datasource_table.name`,
+			`code        = file("functions/function_getuser.js")`,
+		},
+		"resolvers.tf": {
+			`resource "aws_appsync_resolver" "query_user"`,
+			`aws_appsync_function.function_getuser.function_id,`,
+			`request_template  = file("resolvers/Query.users.request.vtl")`,
+		},
+		"imports.tf": {
+			`id = "abc123"`,
+			`id = "abc123-users"`,
+			`id = "abc123-fn1"`,
+			`id = "abc123-Query-user"`,
+		},
+		"resolvers/Query.user.js":              {"`${x}`"},
+		"resolvers/Query.users.response.vtl":   {"$util.toJson($ctx.result)"},
+		"functions/function_getuser.js":        {"export function request() {}"},
+		"backend.tf":                           {`key            = "users_api/terraform.tfstate"`},
+		"main.tf":                              {`required_version = ">= 1.5.0"`},
+		"locals.tf":                            {`project_name = "users_api"`},
+		".gitignore":                           {"*.tfstate"},
+		filepath.Join("resolvers", ".gitkeep"): {""},
+	}
+	for file, contents := range expected {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("unable to read %s: %v", file, err)
+			continue
+		}
+		for _, e := range contents {
+			if !strings.Contains(string(content), e) {
+				t.Errorf("expected %s in %s, got %s", e, file, content)
+			}
+		}
+	}
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.APIID != "abc123" {
+		t.Errorf("expected api id abc123, got %s", manifest.APIID)
+	}
+
+	if err := CreateResources(helpers.ResourceIDs.ImportAppSyncAPI, dest, replacements); err == nil {
+		t.Error("expected error importing into an existing directory, got nil")
+	}
+}
+
+func TestImportAppSyncApiWithoutExport(t *testing.T) {
+	replacements := &messages.CreateResourceMsg{ProjectName: "users_api"}
+
+	if err := CreateResources(helpers.ResourceIDs.ImportAppSyncAPI, t.TempDir(), replacements); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestUniqueIdentifier(t *testing.T) {
+	used := map[string]bool{}
+
+	tcs := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Query_user", expected: "query_user"},
+		{name: "Query user", expected: "query_user_2"},
+		{name: "1st-source", expected: "_1st_source"},
+		{name: "???", expected: "_"},
+	}
+
+	for _, tc := range tcs {
+		if result := uniqueIdentifier(used, tc.name); result != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, result)
+		}
+	}
+}
+
+func TestHclString(t *testing.T) {
+	tcs := []struct {
+		value    string
+		expected string
+	}{
+		{value: "name", expected: `"name"`},
+		{value: `say "hi"`, expected: `"say \"hi\""`},
+		{value: "${var.x} %{if}", expected: `"$${var.x} %%{if}"`},
+	}
+
+	for _, tc := range tcs {
+		if result := hclString(tc.value); result != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package templates
+
+import (
+	"embed"
+
+	"github.com/xsevy/terrapi/i18n"
+)
+
+// localeFiles translate the messages of the templates, other template packs ship their catalogs the same way
+//
+//go:embed locales/*.yaml
+var localeFiles embed.FS
+
+func init() {
+	if err := i18n.Load(localeFiles, "locales"); err != nil {
+		panic(err)
+	}
+}
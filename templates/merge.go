@@ -0,0 +1,146 @@
+package templates
+
+import (
+	"strings"
+)
+
+const (
+	conflictStartMarker     = "<<<<<<< project"
+	conflictSeparatorMarker = "======="
+	conflictEndMarker       = ">>>>>>> template"
+)
+
+// merge3 merges the changes made to base in ours and in theirs line by line, like diff3.
+// Lines changed differently on both sides are written between conflict markers,
+// the number of conflicts is returned with the merged text.
+func merge3(base, ours, theirs string) (string, int) {
+	b, o, t := splitLines(base), splitLines(ours), splitLines(theirs)
+	matchesO := matchLines(b, o)
+	matchesT := matchLines(b, t)
+
+	var merged []string
+	conflicts := 0
+	i, io, it := 0, 0, 0
+
+	for i < len(b) || io < len(o) || it < len(t) {
+		// stable line, unchanged on both sides
+		if i < len(b) && matchesO[i] == io && matchesT[i] == it {
+			merged = append(merged, b[i])
+			i, io, it = i+1, io+1, it+1
+			continue
+		}
+
+		// the chunk ends at the next base line kept on both sides
+		j, endO, endT := i, len(o), len(t)
+		for ; j < len(b); j++ {
+			if matchesO[j] >= 0 && matchesT[j] >= 0 {
+				endO, endT = matchesO[j], matchesT[j]
+				break
+			}
+		}
+
+		chunkB, chunkO, chunkT := b[i:j], o[io:endO], t[it:endT]
+		switch {
+		case equalLines(chunkO, chunkB):
+			merged = append(merged, chunkT...)
+		case equalLines(chunkT, chunkB), equalLines(chunkO, chunkT):
+			merged = append(merged, chunkO...)
+		default:
+			conflicts++
+			merged = append(merged, conflictStartMarker+"\n")
+			merged = append(merged, terminateLines(chunkO)...)
+			merged = append(merged, conflictSeparatorMarker+"\n")
+			merged = append(merged, terminateLines(chunkT)...)
+			merged = append(merged, conflictEndMarker+"\n")
+		}
+		i, io, it = j, endO, endT
+	}
+
+	return strings.Join(merged, ""), conflicts
+}
+
+// commonLines returns the lines of a kept in b, the base of a merge without a common ancestor:
+// lines added on one side are kept and lines which differ on both sides conflict
+func commonLines(a, b string) string {
+	lines := splitLines(a)
+	matches := matchLines(lines, splitLines(b))
+
+	var common []string
+	for i, line := range lines {
+		if matches[i] >= 0 {
+			common = append(common, line)
+		}
+	}
+	return strings.Join(common, "")
+}
+
+// splitLines splits text after every newline, keeping them so the merge preserves the last line ending
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// terminateLines makes sure the last line ends with a newline, so a conflict marker can follow it
+func terminateLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+
+	terminated := append([]string{}, lines...)
+	terminated[len(terminated)-1] += "\n"
+	return terminated
+}
+
+// matchLines returns for every line of a the index of the same line in b, or -1,
+// following the longest common subsequence of both
+func matchLines(a, b []string) []int {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			matches[i] = j
+			i, j = i+1, j+1
+		case j < len(b) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+
+	return matches
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
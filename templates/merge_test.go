@@ -0,0 +1,75 @@
+package templates
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	tcs := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		expected  string
+		conflicts int
+	}{
+		{
+			name:     "no changes",
+			base:     "a\nb\nc\n",
+			ours:     "a\nb\nc\n",
+			theirs:   "a\nb\nc\n",
+			expected: "a\nb\nc\n",
+		},
+		{
+			name:     "changes in different lines",
+			base:     "a\nb\nc\nd\n",
+			ours:     "a\nB\nc\nd\n",
+			theirs:   "a\nb\nc\nD\n",
+			expected: "a\nB\nc\nD\n",
+		},
+		{
+			name:     "insertions on both sides",
+			base:     "a\nb\n",
+			ours:     "start\na\nb\n",
+			theirs:   "a\nb\nend\n",
+			expected: "start\na\nb\nend\n",
+		},
+		{
+			name:     "same change on both sides",
+			base:     "a\nb\nc\n",
+			ours:     "a\nx\nc\n",
+			theirs:   "a\nx\nc\n",
+			expected: "a\nx\nc\n",
+		},
+		{
+			name:     "deletion and change elsewhere",
+			base:     "a\nb\nc\nd\n",
+			ours:     "a\nc\nd\n",
+			theirs:   "a\nb\nc\nD\n",
+			expected: "a\nc\nD\n",
+		},
+		{
+			name:      "conflicting changes",
+			base:      "a\nb\nc\n",
+			ours:      "a\nours\nc\n",
+			theirs:    "a\ntheirs\nc\n",
+			expected:  "a\n<<<<<<< project\nours\n=======\ntheirs\n>>>>>>> template\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "no base",
+			base:      "",
+			ours:      "ours",
+			theirs:    "theirs\n",
+			expected:  "<<<<<<< project\nours\n=======\ntheirs\n>>>>>>> template\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, conflicts := merge3(tc.base, tc.ours, tc.theirs)
+			if merged != tc.expected || conflicts != tc.conflicts {
+				t.Errorf("expected %d conflicts in\n%s\ngot %d in\n%s", tc.conflicts, tc.expected, conflicts, merged)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/i18n"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+// PlannedFile is a file CreateResources writes, Path is slash separated and relative to the project
+type PlannedFile struct {
+	Path string
+	// Modified files exist in the project, the others are created
+	Modified bool
+}
+
+func (f PlannedFile) String() string {
+	if f.Modified {
+		return "~ " + f.Path
+	}
+	return "+ " + f.Path
+}
+
+// Plan lists the files CreateResources writes, without changing dest
+func Plan(id, dest string, replacements *messages.CreateResourceMsg) ([]PlannedFile, error) {
+	if id == helpers.ResourceIDs.CreateAppSyncDataSource {
+		return planDataSource(dest, replacements)
+	}
+
+	// new projects are generated in a temporary directory, they do not depend on the destination
+	tmp, err := os.MkdirTemp("", "terrapi-plan")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := CreateResources(id, tmp, replacements); err != nil {
+		return nil, err
+	}
+
+	dir := ProjectDir(id, tmp, replacements)
+	var files []PlannedFile
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == BaseDirName {
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, PlannedFile{Path: filepath.ToSlash(name)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// planDataSource lists the module files of a data source and the project files it is added to
+func planDataSource(dest string, replacements *messages.CreateResourceMsg) ([]PlannedFile, error) {
+	if _, err := project.FindRoot(dest); err != nil {
+		return nil, err
+	}
+
+	generated, err := renderTemplate(helpers.ResourceIDs.CreateAppSyncDataSource, replacements)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []PlannedFile
+	for _, name := range sortedKeys(generated) {
+		files = append(files, PlannedFile{Path: name})
+	}
+	for _, name := range []string{terraformApiMainFileName, terraformDataSourcesFileName, project.ManifestFileName} {
+		files = append(files, PlannedFile{Path: name, Modified: true})
+	}
+
+	return files, nil
+}
+
+// EnvironmentArgs returns the arguments of terraform init and plan selecting the backend key
+// and the variables of env in a project using environments
+func EnvironmentArgs(env string) (initArgs, planArgs []string) {
+	initArgs = []string{fmt.Sprintf("-backend-config=%s/%s.s3.tfbackend", environmentsDirName, env)}
+	planArgs = []string{fmt.Sprintf("-var-file=%s/%s.tfvars", environmentsDirName, env)}
+	return initArgs, planArgs
+}
+
+// NextSteps tells what to do with the project in dir after CreateResources
+func NextSteps(id, dir string, replacements *messages.CreateResourceMsg) []string {
+	environments := replacements.Environments
+	if manifest, err := project.ReadManifest(dir); err == nil {
+		environments = manifest.Environments
+	}
+
+	init, plan := "terraform init", "terraform plan"
+	if len(environments) > 0 {
+		initArgs, planArgs := EnvironmentArgs(environments[0])
+		init = strings.Join(append([]string{init}, initArgs...), " ")
+		plan = strings.Join(append([]string{plan}, planArgs...), " ")
+	}
+
+	switch id {
+	case helpers.ResourceIDs.CreateAppSyncAPI:
+		return []string{
+			fmt.Sprintf("cd %s", dir),
+			fmt.Sprintf("%s && %s", init, plan),
+			i18n.T("templates.next.add_data_sources", helpers.ResourceName(helpers.ResourceIDs.CreateAppSyncDataSource)),
+			i18n.T("templates.next.check"),
+		}
+	case helpers.ResourceIDs.ImportAppSyncAPI:
+		return []string{
+			fmt.Sprintf("cd %s", dir),
+			i18n.T("templates.next.plan_imports", init, plan),
+			i18n.T("templates.next.remove_imports"),
+			i18n.T("templates.next.drift"),
+		}
+	case helpers.ResourceIDs.CreateAppSyncDataSource:
+		return []string{
+			i18n.T("templates.next.implement_lambda", filepath.Join(dir, replacements.ProjectName)),
+			i18n.T("templates.next.attach_resolvers", replacements.ProjectName),
+			i18n.T("templates.next.plan_module", init, plan),
+		}
+	default:
+		return nil
+	}
+}
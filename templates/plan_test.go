@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+func TestPlan(t *testing.T) {
+	dest := t.TempDir()
+	api := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		AuthorizerLambdaFunction: "authorizer",
+		Environments:             []string{"dev"},
+	}
+
+	files, err := Plan(api.ID, dest, api)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	planned := map[string]PlannedFile{}
+	for _, file := range files {
+		planned[file.Path] = file
+	}
+	for _, name := range []string{"main.tf", "environments/dev.tfvars", project.ManifestFileName} {
+		if file, ok := planned[name]; !ok || file.Modified {
+			t.Errorf("expected %s to be created, got %v", name, files)
+		}
+	}
+	for name := range planned {
+		if strings.HasPrefix(name, BaseDirName) {
+			t.Errorf("unexpected base file %s in the plan", name)
+		}
+	}
+	if entries, _ := os.ReadDir(dest); len(entries) > 0 {
+		t.Errorf("expected the destination to stay empty, got %d entries", len(entries))
+	}
+
+	if err := CreateResources(api.ID, dest, api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataSource := &messages.CreateResourceMsg{
+		ID:            helpers.ResourceIDs.CreateAppSyncDataSource,
+		ProjectName:   "posts",
+		LambdaRuntime: "python3.11",
+	}
+	files, err = Plan(dataSource.ID, filepath.Join(dest, "users"), dataSource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	planned = map[string]PlannedFile{}
+	for _, file := range files {
+		planned[file.Path] = file
+	}
+	if file, ok := planned["posts/lambda.tf"]; !ok || file.Modified {
+		t.Errorf("expected posts/lambda.tf to be created, got %v", files)
+	}
+	if file, ok := planned["main.tf"]; !ok || !file.Modified {
+		t.Errorf("expected main.tf to be modified, got %v", files)
+	}
+
+	if _, err := Plan(dataSource.ID, t.TempDir(), dataSource); !errors.Is(err, project.ErrNoProject) {
+		t.Errorf("expected ErrNoProject outside of a project, got %v", err)
+	}
+}
+
+func TestNextSteps(t *testing.T) {
+	api := &messages.CreateResourceMsg{
+		ID:           helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:  "users",
+		Environments: []string{"dev", "prod"},
+	}
+
+	steps := NextSteps(api.ID, "users", api)
+	if len(steps) == 0 || steps[0] != "cd users" {
+		t.Fatalf("expected cd into the project first, got %v", steps)
+	}
+	if !strings.Contains(steps[1], "-backend-config=environments/dev.s3.tfbackend") {
+		t.Errorf("expected the first environment in the init step, got %s", steps[1])
+	}
+
+	api.Environments = nil
+	steps = NextSteps(api.ID, t.TempDir(), api)
+	if !strings.HasPrefix(steps[1], "terraform init && terraform plan") {
+		t.Errorf("expected plain init and plan without environments, got %s", steps[1])
+	}
+}
@@ -2,10 +2,19 @@ resource "aws_appsync_graphql_api" "appsync" {
   name                = "${local.project_name}_appsync"
   schema              = file("schema.graphql")
   authentication_type = "AWS_LAMBDA"
+  xray_enabled        = true
 
   lambda_authorizer_config {
     authorizer_uri = data.aws_lambda_function.authorizer.arn
   }
-}
 
+  log_config {
+    cloudwatch_logs_role_arn = aws_iam_role.iam_appsync_role.arn
+    field_log_level          = "ERROR"
+  }
+}
 
+resource "aws_cloudwatch_log_group" "appsync" {
+  name              = "/aws/appsync/apis/${aws_appsync_graphql_api.appsync.id}"
+  retention_in_days = local.log_retention_in_days
+}
@@ -2,7 +2,17 @@ terraform {
   backend "s3" {
     bucket         = "{{.BackendBucket}}"
     region         = "{{.AWSRegion}}"
-    key            = "terraform.tfstate"
+{{- if .Environments}}
     dynamodb_table = "{{.BackendLockTable}}"
+
+    # key is set per environment in environments/<environment>.s3.tfbackend
+{{- else}}
+    key            = "{{.BackendKey}}"
+    dynamodb_table = "{{.BackendLockTable}}"
+{{- end}}
+{{- if .BackendWorkspaceKeyPrefix}}
+
+    workspace_key_prefix = "{{.BackendWorkspaceKeyPrefix}}"
+{{- end}}
   }
 }
@@ -1,3 +1,6 @@
+# arn:aws in the commercial regions, arn:aws-cn in China, arn:aws-us-gov in GovCloud
+data "aws_partition" "current" {}
+
 data "aws_iam_policy_document" "iam_lambda_role_document" {
   statement {
     actions = ["sts:AssumeRole"]
@@ -28,19 +31,10 @@ resource "aws_iam_role" "iam_appsync_role" {
   assume_role_policy = data.aws_iam_policy_document.iam_appsync_role_document.json
 }
 
-data "aws_iam_policy_document" "iam_invoke_lambda_policy_document" {
-  statement {
-    actions   = ["lambda:InvokeFunction"]
-    resources = ["*"]
-  }
-}
-
-resource "aws_iam_policy" "iam_invoke_lambda_policy" {
-  name   = "${local.project_name}_iam_invoke_lambda_policy"
-  policy = data.aws_iam_policy_document.iam_invoke_lambda_policy_document.json
-}
+# Data sources add their own inline policies to iam_appsync_role,
+# scoped to the resources they expose (see datasources.tf).
 
-resource "aws_iam_role_policy_attachment" "appsync_invoke_lambda" {
+resource "aws_iam_role_policy_attachment" "appsync_push_to_cloudwatch_logs" {
   role       = aws_iam_role.iam_appsync_role.name
-  policy_arn = aws_iam_policy.iam_invoke_lambda_policy.arn
+  policy_arn = "arn:${data.aws_partition.current.partition}:iam::aws:policy/service-role/AWSAppSyncPushToCloudWatchLogs"
 }
@@ -1,4 +1,11 @@
 locals {
+{{- if .Environments}}
+  project_name = "${var.project_name}_${var.environment}"
+  aws_region   = var.aws_region
+{{- else}}
   project_name = "{{.ProjectName}}"
   aws_region   = "{{.AWSRegion}}"
+{{- end}}
+
+  log_retention_in_days = 14
 }
@@ -4,9 +4,24 @@ terraform {
       source  = "hashicorp/aws"
       version = "~> 5.0"
     }
+    archive = {
+      source  = "hashicorp/archive"
+      version = "~> 2.4"
+    }
+    external = {
+      source  = "hashicorp/external"
+      version = "~> 2.3"
+    }
   }
 }
 
 provider "aws" {
   region = local.aws_region
+
+  default_tags {
+    tags = {
+      Project   = local.project_name
+      ManagedBy = "terrapi"
+    }
+  }
 }
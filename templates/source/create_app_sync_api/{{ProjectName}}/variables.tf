@@ -1 +1,16 @@
+{{- if .Environments -}}
+variable "project_name" {
+  type    = string
+  default = "{{.ProjectName}}"
+}
 
+variable "aws_region" {
+  type    = string
+  default = "{{.AWSRegion}}"
+}
+
+variable "environment" {
+  type        = string
+  description = "Name of the environment, resource names are suffixed with it"
+}
+{{- end}}
@@ -9,5 +9,6 @@ data "archive_file" "zip_layer" {
   type        = "zip"
   source_dir  = "${local.lambda_layer_output_dir}/"
   output_path = local.lambda_layer_zip_path
-  depends_on  = [null_resource.install_dependencies]
+  excludes    = ["requirements.txt"]
+  depends_on  = [data.external.install_dependencies]
 }
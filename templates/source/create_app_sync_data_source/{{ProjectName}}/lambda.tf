@@ -1,11 +1,18 @@
-resource "null_resource" "install_dependencies" {
-  provisioner "local-exec" {
-    command = "pip install -r ${local.lambda_source_dir}/requirements.txt -t ${local.lambda_layer_output_dir}/python"
-  }
+# The layer files are not kept in the repository, they are installed while reading the data source, so a fresh clone
+# installs them again. The installed requirements are copied next to them and compared on the following plans.
+data "external" "install_dependencies" {
+  program = ["sh", "-c", <<-EOT
+    if ! cmp -s "$2" "$1/requirements.txt"; then
+      pip install -r "$2" -t "$1/python" >&2 && cp "$2" "$1/requirements.txt" || exit 1
+    fi
+    echo '{}'
+  EOT
+  , "install_dependencies", local.lambda_layer_output_dir, "${local.lambda_source_dir}/requirements.txt"]
+}
 
-  triggers = {
-    always_run = "${timestamp()}"
-  }
+resource "aws_cloudwatch_log_group" "lambda_log_group" {
+  name              = "/aws/lambda/${local.project_name}"
+  retention_in_days = local.log_retention_in_days
 }
 
 resource "aws_lambda_layer_version" "lambda_layer" {
@@ -24,6 +31,7 @@ resource "aws_lambda_function" "lambda_function" {
   layers           = [aws_lambda_layer_version.lambda_layer.arn]
   source_code_hash = base64sha256(data.archive_file.zip_the_python_code.output_path)
   depends_on = [
-    aws_iam_role_policy_attachment.attach_iam_policy_to_iam_role
+    aws_iam_role_policy_attachment.attach_iam_policy_to_iam_role,
+    aws_cloudwatch_log_group.lambda_log_group,
   ]
 }
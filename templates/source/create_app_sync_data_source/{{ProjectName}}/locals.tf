@@ -1,5 +1,5 @@
 locals {
-  project_name = "{{.ProjectName}}"
+  project_name = "{{.ProjectName}}${var.name_suffix}"
 
   lambda_zip_file_name       = "lambda.zip"
   lambda_zip_path            = "${path.module}/${local.lambda_zip_file_name}"
@@ -8,4 +8,5 @@ locals {
   lambda_layer_zip_path      = "${path.module}/${local.lambda_layer_zip_file_name}"
   lambda_layer_output_dir    = "${path.module}/lambda_layer_files"
   lambda_runtime             = "{{.LambdaRuntime}}"
+  log_retention_in_days      = 14
 }
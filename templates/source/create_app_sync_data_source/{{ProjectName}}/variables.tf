@@ -0,0 +1,5 @@
+variable "name_suffix" {
+  type        = string
+  default     = ""
+  description = "Appended to the names of the resources, used to separate environments"
+}
@@ -1,32 +1,48 @@
 package templates
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
 	"html/template"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/xsevy/terrapi/helpers"
 	"github.com/xsevy/terrapi/helpers/functions"
+	"github.com/xsevy/terrapi/helpers/hcl"
+	"github.com/xsevy/terrapi/helpers/navigation"
 	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
 )
 
-//go:embed source/*/*/.gitignore source/*/*/.terrapi source/*/*/resolvers/.gitkeep source/*
+//go:embed source/*/*/.gitignore source/*/*/resolvers/.gitkeep source/*
 var sourceFiles embed.FS
 
 const (
-	configFileName               = ".terrapi"
 	terraformApiMainFileName     = "main.tf"
 	terraformDataSourcesFileName = "datasources.tf"
+	environmentsDirName          = "environments"
 
 	newModuleContent = `
 module "%s_data_source" {
   source = "./%s"
 }`
+	newEnvironmentModuleContent = `
+module "%[1]s_data_source" {
+  source      = "./%[1]s"
+  name_suffix = "_${var.environment}"
+}`
+	environmentVariablesContent = `environment = "%s"
+aws_region  = "%s"
+`
+	environmentBackendContent = `key = "%s"
+`
 	newDataSourceContent = `
 resource "aws_appsync_datasource" "%s_data_source" {
   name = "${local.project_name}_%s_data_source"
@@ -36,9 +52,45 @@ resource "aws_appsync_datasource" "%s_data_source" {
   lambda_config {
     function_arn = module.%s_data_source.lambda_function_arn
   }
+}`
+	newDataSourcePolicyContent = `
+data "aws_iam_policy_document" "%[1]s_data_source_policy_document" {
+  statement {
+    actions   = [%[2]s]
+    resources = [%[3]s]
+  }
+}
+
+resource "aws_iam_role_policy" "%[1]s_data_source_policy" {
+  name   = "${local.project_name}_%[1]s_data_source_policy"
+  role   = aws_iam_role.iam_appsync_role.id
+  policy = data.aws_iam_policy_document.%[1]s_data_source_policy_document.json
 }`
 )
 
+// dataSourceType is the AppSync data source type, as used by aws_appsync_datasource
+type dataSourceType string
+
+const dataSourceTypeLambda dataSourceType = "AWS_LAMBDA"
+
+// dataSourcePermission lists the actions the AppSync role needs for a data source type.
+// Resources are expressions evaluated against the data source module, so the role is
+// only ever granted access to the specific function. Only Lambda data sources are generated.
+type dataSourcePermission struct {
+	actions   []string
+	resources []string
+}
+
+var dataSourcePermissions = map[dataSourceType]dataSourcePermission{
+	dataSourceTypeLambda: {
+		actions: []string{"lambda:InvokeFunction"},
+		resources: []string{
+			"module.%s_data_source.lambda_function_arn",
+			`"${module.%s_data_source.lambda_function_arn}:*"`,
+		},
+	},
+}
+
 type creationRecord struct {
 	createdPaths []string
 }
@@ -64,6 +116,8 @@ func CreateResources(id, dest string, replacements *messages.CreateResourceMsg)
 		f = createAppSyncApi
 	case helpers.ResourceIDs.CreateAppSyncDataSource:
 		f = createAppSyncDataSource
+	case helpers.ResourceIDs.ImportAppSyncAPI:
+		f = importAppSyncApi
 	default:
 		return fmt.Errorf("ID %s not found in ResourceIDs", id)
 	}
@@ -71,13 +125,90 @@ func CreateResources(id, dest string, replacements *messages.CreateResourceMsg)
 	return f(src, dest, replacements)
 }
 
-// createAppSyncDataSource creates resources for AppSync API
+// Summary describes what CreateResources does, it is used as the commit message of the operation
+func Summary(id string, replacements *messages.CreateResourceMsg) string {
+	switch id {
+	case helpers.ResourceIDs.CreateAppSyncAPI:
+		return fmt.Sprintf("Create AppSync API %s", replacements.ProjectName)
+	case helpers.ResourceIDs.ImportAppSyncAPI:
+		return fmt.Sprintf("Import AppSync API %s", replacements.ProjectName)
+	case helpers.ResourceIDs.CreateAppSyncDataSource:
+		return fmt.Sprintf("Add data source %s", replacements.ProjectName)
+	default:
+		return fmt.Sprintf("Create %s", replacements.ProjectName)
+	}
+}
+
+// ProjectDir returns the directory of the terraform project affected by CreateResources
+func ProjectDir(id, dest string, replacements *messages.CreateResourceMsg) string {
+	if id == helpers.ResourceIDs.CreateAppSyncAPI || id == helpers.ResourceIDs.ImportAppSyncAPI {
+		return filepath.Join(dest, replacements.ProjectName)
+	}
+
+	// data sources are modules of the API project containing dest
+	return project.FindRootOrDir(dest)
+}
+
+// UsedDataSourceNames returns the names a new data source of the project containing dir can not have,
+// the data source modules of the project and the files and directories the module would overwrite
+func UsedDataSourceNames(dir string) ([]string, error) {
+	root, err := project.FindRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	dataSources, err := DataSourceNames(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(names, dataSources...), nil
+}
+
+// DataSourceNames returns the names of the data source modules of the project containing dir
+func DataSourceNames(dir string) ([]string, error) {
+	root, err := project.FindRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(root, terraformApiMainFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var names []string
+	for _, block := range hcl.Parse(strings.Split(string(content), "\n")) {
+		if name, ok := strings.CutSuffix(block.Label(0), "_data_source"); ok && block.Type == "module" {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// createAppSyncDataSource adds a data source module to the AppSync API project containing dest
 func createAppSyncDataSource(src, dest string, replacements *messages.CreateResourceMsg) error {
 	if err := checkRequiredFields(replacements.ProjectName); err != nil {
 		return err
 	}
 
-	if err := checkConfigFileExists(); err != nil {
+	dest, err := project.FindRoot(dest)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := project.ReadManifest(dest)
+	if err != nil {
 		return err
 	}
 
@@ -87,7 +218,10 @@ func createAppSyncDataSource(src, dest string, replacements *messages.CreateReso
 
 	// adding new module to main file
 	newModuleContent := fmt.Sprintf(newModuleContent, replacements.ProjectName, replacements.ProjectName)
-	if err := functions.AppendTextToFile(terraformApiMainFileName, newModuleContent, true); err != nil {
+	if manifest.HasEnvironments() {
+		newModuleContent = fmt.Sprintf(newEnvironmentModuleContent, replacements.ProjectName)
+	}
+	if err := functions.AppendTextToFile(filepath.Join(dest, terraformApiMainFileName), newModuleContent, true); err != nil {
 		return err
 	}
 
@@ -98,11 +232,48 @@ func createAppSyncDataSource(src, dest string, replacements *messages.CreateReso
 		replacements.ProjectName,
 		replacements.ProjectName,
 	)
-	if err := functions.AppendTextToFile(terraformDataSourcesFileName, newDataSourceContent, true); err != nil {
+	if err := functions.AppendTextToFile(filepath.Join(dest, terraformDataSourcesFileName), newDataSourceContent, true); err != nil {
 		return err
 	}
 
-	return nil
+	// granting the appsync role access to the data source only
+	newDataSourcePolicyContent, err := dataSourcePolicy(dataSourceTypeLambda, replacements.ProjectName)
+	if err != nil {
+		return err
+	}
+	if err := functions.AppendTextToFile(filepath.Join(dest, terraformDataSourcesFileName), newDataSourcePolicyContent, true); err != nil {
+		return err
+	}
+
+	if err := recordTemplate(dest, manifest, helpers.ResourceIDs.CreateAppSyncDataSource, replacements); err != nil {
+		return err
+	}
+	return manifest.Write(dest)
+}
+
+// dataSourcePolicy returns the AppSync role policy scoped to the given data source
+func dataSourcePolicy(t dataSourceType, name string) (string, error) {
+	permission, ok := dataSourcePermissions[t]
+	if !ok {
+		return "", fmt.Errorf("unsupported data source type %s", t)
+	}
+
+	actions := make([]string, 0, len(permission.actions))
+	for _, action := range permission.actions {
+		actions = append(actions, fmt.Sprintf("%q", action))
+	}
+
+	resources := make([]string, 0, len(permission.resources))
+	for _, resource := range permission.resources {
+		resources = append(resources, fmt.Sprintf(resource, name))
+	}
+
+	return fmt.Sprintf(
+		newDataSourcePolicyContent,
+		name,
+		strings.Join(actions, ", "),
+		strings.Join(resources, ", "),
+	), nil
 }
 
 // createAppSyncApi creates resources for AppSync API
@@ -110,21 +281,101 @@ func createAppSyncApi(src, dest string, replacements *messages.CreateResourceMsg
 	if err := checkRequiredFields(replacements.ProjectName); err != nil {
 		return err
 	}
+	if err := validateEnvironments(nil, replacements.Environments...); err != nil {
+		return err
+	}
 
 	if err := copyFiles(sourceFiles, src, dest, replacements); err != nil {
 		return err
 	}
 
+	dir := filepath.Join(dest, replacements.ProjectName)
+	manifest := &project.Manifest{
+		Name:       replacements.ProjectName,
+		Region:     replacements.AWSRegion,
+		BackendKey: replacements.BackendKey,
+		Git:        replacements.GitInit,
+	}
+	if err := recordTemplate(dir, manifest, helpers.ResourceIDs.CreateAppSyncAPI, replacements); err != nil {
+		return err
+	}
+	if err := manifest.Write(dir); err != nil {
+		return err
+	}
+
+	for _, env := range replacements.Environments {
+		if err := writeEnvironment(dir, manifest, env); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddEnvironment adds a new environment to the project in dir, which must use the environments layout
+func AddEnvironment(dir, env string) error {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	if !manifest.HasEnvironments() {
+		return fmt.Errorf("project %s does not use the environments layout", dir)
+	}
+	if err := validateEnvironments(manifest.Environments, env); err != nil {
+		return err
+	}
+
+	return writeEnvironment(dir, manifest, env)
+}
+
+// validateEnvironments checks the new environments before any file is written. They are used in resource
+// names, so they follow the rule of the names of the form, and must not be in existing.
+func validateEnvironments(existing []string, envs ...string) error {
+	seen := slices.Clone(existing)
+	for _, env := range envs {
+		if err := navigation.AWSName()(env); err != nil {
+			return fmt.Errorf("invalid environment name %q: %v", env, err)
+		}
+		if slices.Contains(seen, env) {
+			return fmt.Errorf("environment %s already exists", env)
+		}
+		seen = append(seen, env)
+	}
 	return nil
 }
 
-func checkConfigFileExists() error {
-	_, err := os.Stat(configFileName)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("missing %s config file", configFileName)
+// writeEnvironment creates the variables and backend config of the environment, checked by validateEnvironments,
+// and records it in the manifest
+func writeEnvironment(dir string, manifest *project.Manifest, env string) error {
+	envDir := filepath.Join(dir, environmentsDirName)
+	if err := createDirectory(envDir); err != nil {
+		return err
 	}
 
-	return err
+	files := map[string]string{
+		env + ".tfvars":       fmt.Sprintf(environmentVariablesContent, env, manifest.Region),
+		env + ".s3.tfbackend": fmt.Sprintf(environmentBackendContent, EnvironmentBackendKey(manifest.Name, manifest.BackendKey, env)),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(envDir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("error creating file %s: %v", name, err)
+		}
+	}
+
+	manifest.Environments = append(manifest.Environments, env)
+	return manifest.Write(dir)
+}
+
+// EnvironmentBackendKey puts the state of the environment next to the state key of the project,
+// which defaults to <name>/terraform.tfstate
+func EnvironmentBackendKey(name, backendKey, env string) string {
+	key := backendKey
+	if key == "" {
+		key = path.Join(name, "terraform.tfstate")
+	}
+
+	return path.Join(path.Dir(key), env, path.Base(key))
 }
 
 // checkRequiredFields checks if required fields are present
@@ -185,27 +436,36 @@ func createDirectory(path string) error {
 
 // createFile copies a file from source and replaces values using text/template
 func createFile(fsys fs.FS, src, dest string, replacements *messages.CreateResourceMsg) error {
-	data, err := fs.ReadFile(fsys, src)
+	content, err := renderFile(fsys, src, replacements)
 	if err != nil {
 		return err
 	}
 
-	tmpl, err := template.New("file").Parse(string(data))
+	if err := os.WriteFile(dest, content, 0644); err != nil {
+		return fmt.Errorf("error creating file %s: %v", dest, err)
+	}
+
+	return nil
+}
+
+// renderFile returns the content of a source file with the values replaced
+func renderFile(fsys fs.FS, src string, replacements *messages.CreateResourceMsg) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, src)
 	if err != nil {
-		return fmt.Errorf("error creating template: %s %v", data, err)
+		return nil, err
 	}
 
-	file, err := os.Create(dest)
+	tmpl, err := template.New("file").Parse(string(data))
 	if err != nil {
-		return fmt.Errorf("error creating file %s: %v", dest, err)
+		return nil, fmt.Errorf("error creating template: %s %v", data, err)
 	}
-	defer file.Close()
 
-	if err := tmpl.Execute(file, replacements); err != nil {
-		return fmt.Errorf("error executing template: %v", err)
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, replacements); err != nil {
+		return nil, fmt.Errorf("error executing template: %v", err)
 	}
 
-	return nil
+	return b.Bytes(), nil
 }
 
 // renameFile replaces placeholders in the data string with values from the replacements struct.
@@ -1,12 +1,20 @@
 package templates
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"testing/fstest"
 
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/linter"
 	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
 )
 
 func TestRenameFile(t *testing.T) {
@@ -358,3 +366,352 @@ func TestCopyFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestDataSourcePolicy(t *testing.T) {
+	tcs := []struct {
+		name        string
+		t           dataSourceType
+		expected    []string
+		expectError bool
+	}{
+		{
+			name: "Lambda data source",
+			t:    dataSourceTypeLambda,
+			expected: []string{
+				`data "aws_iam_policy_document" "users_data_source_policy_document"`,
+				`actions   = ["lambda:InvokeFunction"]`,
+				`resources = [module.users_data_source.lambda_function_arn, "${module.users_data_source.lambda_function_arn}:*"]`,
+				`role   = aws_iam_role.iam_appsync_role.id`,
+			},
+			expectError: false,
+		},
+		{
+			name:        "DynamoDB data source",
+			t:           dataSourceType("AMAZON_DYNAMODB"),
+			expectError: true,
+		},
+		{
+			name:        "Unknown data source",
+			t:           dataSourceType("HTTP"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := dataSourcePolicy(tc.t, "users")
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			for _, e := range tc.expected {
+				if !strings.Contains(result, e) {
+					t.Errorf("expected %s in %s", e, result)
+				}
+			}
+		})
+	}
+}
+
+func TestSourceFilesPassLint(t *testing.T) {
+	issues, err := linter.Lint(sourceFiles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, issue := range issues {
+		t.Errorf("unexpected issue in template: %s", issue)
+	}
+}
+
+func TestDataSourceLayerDependencies(t *testing.T) {
+	data, err := sourceFiles.ReadFile("source/create_app_sync_data_source/{{ProjectName}}/lambda.tf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, script, _ := strings.Cut(string(data), "<<-EOT\n")
+	script, _, _ = strings.Cut(script, "EOT")
+
+	// pip counts its runs, so the test can tell when the layer files were installed again
+	bin, dir := t.TempDir(), t.TempDir()
+	pip := "#!/bin/sh\necho run >> " + filepath.Join(dir, "pip_runs") + "\n"
+	if err := os.WriteFile(filepath.Join(bin, "pip"), []byte(pip), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	requirements := filepath.Join(dir, "requirements.txt")
+	output := filepath.Join(dir, "lambda_layer_files")
+
+	install := func(content string) int {
+		if err := os.WriteFile(requirements, []byte(content), 0o644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		os.MkdirAll(output, 0o755)
+		result, err := exec.Command("sh", "-c", script, "install_dependencies", output, requirements).Output()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.TrimSpace(string(result)) != "{}" {
+			t.Errorf("expected an empty JSON object, got %q", result)
+		}
+		runs, _ := os.ReadFile(filepath.Join(dir, "pip_runs"))
+		return strings.Count(string(runs), "run")
+	}
+
+	if runs := install("boto3\n"); runs != 1 {
+		t.Errorf("expected the first plan to install the layer, got %d runs", runs)
+	}
+	if runs := install("boto3\n"); runs != 1 {
+		t.Errorf("expected the installed layer to be kept, got %d runs", runs)
+	}
+	if runs := install("boto3\nrequests\n"); runs != 2 {
+		t.Errorf("expected the changed requirements to be installed, got %d runs", runs)
+	}
+	// a fresh clone has no layer files, they are not in the repository
+	os.RemoveAll(output)
+	if runs := install("boto3\nrequests\n"); runs != 3 {
+		t.Errorf("expected the missing layer to be installed, got %d runs", runs)
+	}
+}
+
+func TestProjectDir(t *testing.T) {
+	replacements := &messages.CreateResourceMsg{ProjectName: "users"}
+
+	if dir := ProjectDir(helpers.ResourceIDs.CreateAppSyncAPI, "./", replacements); dir != "users" {
+		t.Errorf("expected users, got %s", dir)
+	}
+
+	if dir := ProjectDir(helpers.ResourceIDs.CreateAppSyncDataSource, "./", replacements); dir != "./" {
+		t.Errorf("expected ./, got %s", dir)
+	}
+}
+
+func TestBackendTemplate(t *testing.T) {
+	tcs := []struct {
+		name         string
+		replacements messages.CreateResourceMsg
+		expected     []string
+		unexpected   []string
+	}{
+		{
+			name: "Without workspace key prefix",
+			replacements: messages.CreateResourceMsg{
+				BackendBucket: "bucket",
+				BackendKey:    "users/terraform.tfstate",
+			},
+			expected:   []string{`key            = "users/terraform.tfstate"`},
+			unexpected: []string{"workspace_key_prefix"},
+		},
+		{
+			name: "With workspace key prefix",
+			replacements: messages.CreateResourceMsg{
+				BackendBucket:             "bucket",
+				BackendKey:                "users/terraform.tfstate",
+				BackendWorkspaceKeyPrefix: "users",
+			},
+			expected: []string{
+				`key            = "users/terraform.tfstate"`,
+				`workspace_key_prefix = "users"`,
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "backend.tf")
+
+			err := createFile(sourceFiles, "source/create_app_sync_api/{{ProjectName}}/backend.tf", dest, &tc.replacements)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			content, _ := os.ReadFile(dest)
+			for _, e := range tc.expected {
+				if !strings.Contains(string(content), e) {
+					t.Errorf("expected %s in %s", e, content)
+				}
+			}
+			for _, u := range tc.unexpected {
+				if strings.Contains(string(content), u) {
+					t.Errorf("unexpected %s in %s", u, content)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAppSyncApiWithEnvironments(t *testing.T) {
+	dest := t.TempDir()
+	replacements := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		BackendLockTable:         "lock",
+		BackendKey:               "users/terraform.tfstate",
+		AuthorizerLambdaFunction: "authorizer",
+		Environments:             []string{"dev", "prod"},
+	}
+
+	if err := CreateResources(replacements.ID, dest, replacements); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+
+	expected := map[string]string{
+		"environments/dev.tfvars":        `environment = "dev"`,
+		"environments/prod.s3.tfbackend": `key = "users/prod/terraform.tfstate"`,
+		"variables.tf":                   `variable "environment"`,
+		"locals.tf":                      `project_name = "${var.project_name}_${var.environment}"`,
+	}
+	for file, e := range expected {
+		content, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Errorf("unable to read %s: %v", file, err)
+			continue
+		}
+		if !strings.Contains(string(content), e) {
+			t.Errorf("expected %s in %s, got %s", e, file, content)
+		}
+	}
+
+	if err := AddEnvironment(dir, "stage"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "environments/stage.tfvars")); err != nil {
+		t.Errorf("environment stage was not created: %v", err)
+	}
+
+	if err := AddEnvironment(dir, "dev"); err == nil {
+		t.Error("expected error adding an existing environment, got nil")
+	}
+
+	for _, env := range []string{"Bad Name", "my-env", "1st"} {
+		if err := AddEnvironment(dir, env); err == nil {
+			t.Errorf("expected error adding the invalid environment %s, got nil", env)
+		}
+	}
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(manifest.Environments, ",") != "dev,prod,stage" {
+		t.Errorf("unexpected environments in manifest %v", manifest.Environments)
+	}
+}
+
+func TestCreateAppSyncApiWithInvalidEnvironment(t *testing.T) {
+	dest := t.TempDir()
+	replacements := &messages.CreateResourceMsg{
+		ID:           helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:  "users",
+		AWSRegion:    "eu-central-1",
+		Environments: []string{"dev", "pre-prod"},
+	}
+
+	if err := CreateResources(replacements.ID, dest, replacements); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	// nothing is written when an environment is invalid
+	if _, err := os.Stat(filepath.Join(dest, "users")); !os.IsNotExist(err) {
+		t.Errorf("expected no project, got %v", err)
+	}
+}
+
+func TestAddEnvironmentWithoutEnvironmentsLayout(t *testing.T) {
+	dir := t.TempDir()
+	manifest := &project.Manifest{Name: "users"}
+	if err := manifest.Write(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := AddEnvironment(dir, "dev"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestCreateAppSyncDataSourceFromNestedDirectory(t *testing.T) {
+	dest := t.TempDir()
+	api := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		AuthorizerLambdaFunction: "authorizer",
+	}
+	if err := CreateResources(api.ID, dest, api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root := filepath.Join(dest, "users")
+
+	dataSource := &messages.CreateResourceMsg{
+		ID:            helpers.ResourceIDs.CreateAppSyncDataSource,
+		ProjectName:   "posts",
+		LambdaRuntime: "python3.11",
+	}
+	nested := filepath.Join(root, "resolvers")
+	if dir := ProjectDir(dataSource.ID, nested, dataSource); dir != root {
+		t.Errorf("expected project dir %s, got %s", root, dir)
+	}
+	if err := CreateResources(dataSource.ID, nested, dataSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "posts", "lambda.tf")); err != nil {
+		t.Errorf("expected the module in the project root: %v", err)
+	}
+	main, err := os.ReadFile(filepath.Join(root, "main.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(main), `module "posts_data_source"`) {
+		t.Errorf("expected the module in main.tf, got %s", main)
+	}
+
+	if err := CreateResources(dataSource.ID, t.TempDir(), dataSource); !errors.Is(err, project.ErrNoProject) {
+		t.Errorf("expected ErrNoProject outside of a project, got %v", err)
+	}
+}
+
+func TestUsedDataSourceNames(t *testing.T) {
+	root := t.TempDir()
+	if err := (&project.Manifest{Name: "users"}).Write(root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "resolvers"), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	main := "module \"posts_data_source\" {\n  source = \"./posts\"\n}\n\nmodule \"cache\" {\n  source = \"./cache\"\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.tf"), []byte(main), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names, err := UsedDataSourceNames(filepath.Join(root, "resolvers"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{".terrapi", "main.tf", "resolvers", "posts"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	if _, err := UsedDataSourceNames(t.TempDir()); !errors.Is(err, project.ErrNoProject) {
+		t.Errorf("expected ErrNoProject outside of a project, got %v", err)
+	}
+
+	dataSources, err := DataSourceNames(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dataSources, []string{"posts"}) {
+		t.Errorf("expected [posts], got %v", dataSources)
+	}
+}
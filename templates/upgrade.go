@@ -0,0 +1,335 @@
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+// BaseDirName holds pristine copies of the generated files, the common ancestor of the three-way merge
+const BaseDirName = ".terrapi-base"
+
+// templateVersions must be bumped whenever the output of a template changes,
+// terrapi upgrade re-renders the templates recorded with an older version
+var templateVersions = map[string]int{
+	helpers.ResourceIDs.CreateAppSyncAPI:        3,
+	helpers.ResourceIDs.CreateAppSyncDataSource: 3,
+	helpers.ResourceIDs.ImportAppSyncAPI:        1,
+}
+
+// FileStatus tells what Upgrade did with a generated file
+type FileStatus string
+
+const (
+	// FileUpdated was not modified in the project and was replaced by the new template output
+	FileUpdated FileStatus = "updated"
+	// FileMerged was modified in the project, the template changes were merged into it
+	FileMerged FileStatus = "merged"
+	// FileConflict was modified in the same lines as the template, conflict markers were written
+	FileConflict FileStatus = "conflict"
+	// FileAdded is new in the template
+	FileAdded FileStatus = "added"
+	// FileSkipped was deleted from the project and is not recreated
+	FileSkipped FileStatus = "skipped"
+	// FileUntracked is no longer generated by the template, it is left in the project
+	FileUntracked FileStatus = "untracked"
+)
+
+// UpgradedFile is a file affected by Upgrade, Path is relative to the project
+type UpgradedFile struct {
+	Path   string
+	Status FileStatus
+}
+
+func (f UpgradedFile) String() string {
+	return fmt.Sprintf("%-9s %s", f.Status, f.Path)
+}
+
+// Upgrade re-renders the templates of the project in dir which were generated by an older
+// version of terrapi and merges the new output with the changes made in the project since.
+// Projects which do not record their templates are adopted, their templates are inferred from their files.
+// When dryRun is set the files which would change are returned but nothing is written.
+func Upgrade(dir string, dryRun bool) ([]UpgradedFile, error) {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// projects generated before the manifest recorded the templates are adopted first
+	if len(manifest.Templates) == 0 {
+		if err := adoptTemplates(dir, manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	var upgraded []UpgradedFile
+	for i, record := range manifest.Templates {
+		version, ok := templateVersions[record.ID]
+		if !ok {
+			return nil, fmt.Errorf("unknown template %s", record.ID)
+		}
+		if record.Version >= version {
+			continue
+		}
+
+		files, err := upgradeTemplate(dir, manifest, &manifest.Templates[i], dryRun)
+		if err != nil {
+			return nil, err
+		}
+		upgraded = append(upgraded, files...)
+	}
+
+	if dryRun {
+		return upgraded, nil
+	}
+
+	return upgraded, manifest.Write(dir)
+}
+
+// upgradeTemplate merges the new output of a template into the project and updates its record
+func upgradeTemplate(dir string, manifest *project.Manifest, record *project.Template, dryRun bool) ([]UpgradedFile, error) {
+	replacements := templateReplacements(record.ID, record.Values, manifest)
+	generated, err := renderTemplate(record.ID, replacements)
+	if err != nil {
+		return nil, err
+	}
+
+	var upgraded []UpgradedFile
+	hashes := map[string]string{}
+
+	for _, name := range sortedKeys(generated) {
+		content := generated[name]
+		hashes[name] = hashContent(content)
+
+		status, merged, err := upgradeFile(dir, name, record.Files[name], content)
+		if err != nil {
+			return nil, err
+		}
+		if status != "" {
+			upgraded = append(upgraded, UpgradedFile{Path: name, Status: status})
+		}
+
+		if dryRun {
+			continue
+		}
+		if merged != nil {
+			if err := writeProjectFile(dir, name, merged); err != nil {
+				return nil, err
+			}
+		}
+		if err := writeProjectFile(filepath.Join(dir, BaseDirName), name, content); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedKeys(record.Files) {
+		if _, ok := generated[name]; ok {
+			continue
+		}
+		upgraded = append(upgraded, UpgradedFile{Path: name, Status: FileUntracked})
+		if !dryRun {
+			os.Remove(filepath.Join(dir, BaseDirName, filepath.FromSlash(name)))
+		}
+	}
+
+	record.Version = templateVersions[record.ID]
+	// drops the values recorded by older versions which are not read by the templates
+	record.Values = templateValues(replacements)
+	record.Files = hashes
+
+	return upgraded, nil
+}
+
+// upgradeFile decides what happens to a single generated file, it returns the content to write,
+// or nil when the file in the project stays as it is
+func upgradeFile(dir string, name string, recordedHash string, generated []byte) (FileStatus, []byte, error) {
+	current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return "", nil, err
+	}
+
+	switch {
+	case recordedHash == "" && !exists:
+		return FileAdded, generated, nil
+	case !exists:
+		return FileSkipped, nil, nil
+	case bytes.Equal(current, generated):
+		return "", nil, nil
+	case hashContent(current) == recordedHash:
+		return FileUpdated, generated, nil
+	case hashContent(generated) == recordedHash:
+		// the template did not change this file, the project changes are kept
+		return "", nil, nil
+	}
+
+	base, err := os.ReadFile(filepath.Join(dir, BaseDirName, filepath.FromSlash(name)))
+	if os.IsNotExist(err) {
+		// adopted projects have no base copy, the lines both sides have in common are the base
+		base = []byte(commonLines(string(current), string(generated)))
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	merged, conflicts := merge3(string(base), string(current), string(generated))
+	if merged == string(current) {
+		// the project already has the template changes, like the content appended to an adopted file
+		return "", nil, nil
+	}
+	if conflicts > 0 {
+		return FileConflict, []byte(merged), nil
+	}
+	return FileMerged, []byte(merged), nil
+}
+
+// recordTemplate adds the template to the manifest and keeps a pristine copy of its files in the
+// project, the caller writes the manifest
+func recordTemplate(dir string, manifest *project.Manifest, id string, replacements *messages.CreateResourceMsg) error {
+	generated, err := renderTemplate(id, replacements)
+	if err != nil {
+		return err
+	}
+
+	record := project.Template{
+		ID:      id,
+		Version: templateVersions[id],
+		Values:  templateValues(replacements),
+		Files:   map[string]string{},
+	}
+	for name, content := range generated {
+		record.Files[name] = hashContent(content)
+		if err := writeProjectFile(filepath.Join(dir, BaseDirName), name, content); err != nil {
+			return err
+		}
+	}
+
+	manifest.Templates = append(manifest.Templates, record)
+	return nil
+}
+
+// renderTemplate returns the files a template generates, by slash separated path relative to the project.
+// Content appended to existing files and environment files are not part of the output.
+func renderTemplate(id string, replacements *messages.CreateResourceMsg) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	if id == helpers.ResourceIDs.ImportAppSyncAPI {
+		// the rest of an imported project depends on the API it was imported from
+		apiSrc := path.Join("source", helpers.ResourceIDs.CreateAppSyncAPI, "{{ProjectName}}")
+		for _, name := range importedAPIFiles {
+			content, err := renderFile(sourceFiles, path.Join(apiSrc, name), replacements)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = content
+		}
+		return files, nil
+	}
+
+	src := path.Join("source", id)
+	err := fs.WalkDir(sourceFiles, src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		name, err := renameFile(strings.TrimPrefix(p, src+"/"), replacements)
+		if err != nil {
+			return err
+		}
+		// API projects are rendered into their own directory, data sources into a module of the project
+		if id == helpers.ResourceIDs.CreateAppSyncAPI {
+			name = strings.TrimPrefix(name, replacements.ProjectName+"/")
+		}
+
+		content, err := renderFile(sourceFiles, p, replacements)
+		if err != nil {
+			return err
+		}
+		files[name] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// templateFields are the fields of the replacements read by the templates, only they are recorded in the manifest.
+// Environments are recorded in the manifest itself, the other fields describe the run creating the resource.
+var templateFields = []string{
+	"ProjectName",
+	"LambdaRuntime",
+	"AWSRegion",
+	"BackendBucket",
+	"BackendLockTable",
+	"BackendKey",
+	"BackendWorkspaceKeyPrefix",
+	"AuthorizerLambdaFunction",
+}
+
+// templateValues returns the string replacements a template is rendered with
+func templateValues(replacements *messages.CreateResourceMsg) map[string]string {
+	values := map[string]string{}
+
+	v := reflect.ValueOf(replacements).Elem()
+	for _, name := range templateFields {
+		if value := v.FieldByName(name); !value.IsZero() {
+			values[name] = value.String()
+		}
+	}
+
+	return values
+}
+
+// templateReplacements restores the replacements of a template from its recorded values
+func templateReplacements(id string, values map[string]string, manifest *project.Manifest) *messages.CreateResourceMsg {
+	replacements := &messages.CreateResourceMsg{ID: id, Environments: manifest.Environments}
+
+	v := reflect.ValueOf(replacements).Elem()
+	for _, name := range templateFields {
+		if value, ok := values[name]; ok {
+			v.FieldByName(name).SetString(value)
+		}
+	}
+
+	return replacements
+}
+
+// writeProjectFile writes a file given by slash separated path relative to dir, creating its directory
+func writeProjectFile(dir string, name string, content []byte) error {
+	dest := filepath.Join(dir, filepath.FromSlash(name))
+	if err := createDirectory(filepath.Dir(dest)); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(dest, content, 0644); err != nil {
+		return fmt.Errorf("error creating file %s: %v", dest, err)
+	}
+	return nil
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,338 @@
+package templates
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/xsevy/terrapi/helpers"
+	"github.com/xsevy/terrapi/messages"
+	"github.com/xsevy/terrapi/project"
+)
+
+func TestCreateRecordsTemplate(t *testing.T) {
+	dest := t.TempDir()
+	replacements := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		AuthorizerLambdaFunction: "authorizer",
+		Destination:              dest,
+		GitInit:                  true,
+	}
+	if err := CreateResources(replacements.ID, dest, replacements); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(manifest.Templates) != 1 {
+		t.Fatalf("expected 1 template record, got %d", len(manifest.Templates))
+	}
+
+	record := manifest.Templates[0]
+	if record.ID != helpers.ResourceIDs.CreateAppSyncAPI || record.Version != templateVersions[record.ID] {
+		t.Errorf("unexpected record %s %d", record.ID, record.Version)
+	}
+	// the destination is a local path, it is not read by the templates
+	expected := map[string]string{
+		"ProjectName":              "users",
+		"AWSRegion":                "eu-central-1",
+		"BackendBucket":            "bucket",
+		"AuthorizerLambdaFunction": "authorizer",
+	}
+	if !reflect.DeepEqual(record.Values, expected) {
+		t.Errorf("expected values %v, got %v", expected, record.Values)
+	}
+
+	for _, name := range []string{"main.tf", "appsync.tf", "resolvers/.gitkeep"} {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if record.Files[name] != hashContent(content) {
+			t.Errorf("hash of %s does not match the generated file", name)
+		}
+
+		base, err := os.ReadFile(filepath.Join(dir, BaseDirName, filepath.FromSlash(name)))
+		if err != nil || string(base) != string(content) {
+			t.Errorf("expected a base copy of %s, got %v", name, err)
+		}
+	}
+
+	upgraded, err := Upgrade(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upgraded) != 0 {
+		t.Errorf("expected an up to date project, got %v", upgraded)
+	}
+}
+
+func TestTemplateFields(t *testing.T) {
+	fields := map[string]bool{"Environments": true}
+	for _, name := range templateFields {
+		fields[name] = true
+	}
+
+	// every field used in the names or the content of the source files must be recorded,
+	// the import templates are rendered with the exported API instead
+	actionRegex := regexp.MustCompile(`\{\{[^}]*\}\}`)
+	fieldRegex := regexp.MustCompile(`\b[A-Z][A-Za-z]*\b`)
+	err := fs.WalkDir(sourceFiles, "source", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == importTemplatesDir {
+			return fs.SkipDir
+		}
+		used := p
+		if !d.IsDir() {
+			content, err := fs.ReadFile(sourceFiles, p)
+			if err != nil {
+				return err
+			}
+			used += string(content)
+		}
+		for _, action := range actionRegex.FindAllString(used, -1) {
+			for _, field := range fieldRegex.FindAllString(action, -1) {
+				if !fields[field] {
+					t.Errorf("%s reads %s, which is not in templateFields", p, field)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	dest := t.TempDir()
+	replacements := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		AuthorizerLambdaFunction: "authorizer",
+	}
+	if err := CreateResources(replacements.ID, dest, replacements); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(content)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// pretend the project was generated by a previous template version,
+	// which differed from the current one in a single line of each file
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record := &manifest.Templates[0]
+	record.Version = 0
+	// previous versions recorded the destination too
+	record.Values["Destination"] = dest
+
+	old := map[string]string{
+		"appsync.tf": strings.Replace(read("appsync.tf"), "xray_enabled        = true", "xray_enabled        = false", 1),
+		"locals.tf":  strings.Replace(read("locals.tf"), "log_retention_in_days = 14", "log_retention_in_days = 7", 1),
+		"iam.tf":     strings.Replace(read("iam.tf"), "AWSAppSyncPushToCloudWatchLogs", "AdministratorAccess", 1),
+	}
+	for name, content := range old {
+		write(name, content)
+		write(filepath.Join(BaseDirName, name), content)
+		record.Files[name] = hashContent([]byte(content))
+	}
+	delete(record.Files, "outputs.tf")
+	os.Remove(filepath.Join(dir, "outputs.tf"))
+	if err := manifest.Write(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// user changes, next to the template change in locals.tf and away from it in appsync.tf
+	write("appsync.tf", strings.Replace(old["appsync.tf"], `field_log_level          = "ERROR"`, `field_log_level          = "ALL"`, 1))
+	write("locals.tf", strings.Replace(old["locals.tf"], "log_retention_in_days = 7", "log_retention_in_days = 30", 1))
+
+	dryRun, err := Upgrade(dir, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if read("iam.tf") != old["iam.tf"] {
+		t.Error("dry run changed iam.tf")
+	}
+
+	upgraded, err := Upgrade(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]FileStatus{
+		"appsync.tf": FileMerged,
+		"iam.tf":     FileUpdated,
+		"locals.tf":  FileConflict,
+		"outputs.tf": FileAdded,
+	}
+	for _, files := range [][]UpgradedFile{dryRun, upgraded} {
+		statuses := map[string]FileStatus{}
+		for _, f := range files {
+			statuses[f.Path] = f.Status
+		}
+		if len(statuses) != len(expected) {
+			t.Errorf("expected %v, got %v", expected, statuses)
+		}
+		for name, status := range expected {
+			if statuses[name] != status {
+				t.Errorf("expected %s to be %s, got %s", name, status, statuses[name])
+			}
+		}
+	}
+
+	appsync := read("appsync.tf")
+	if !strings.Contains(appsync, "xray_enabled        = true") || !strings.Contains(appsync, `field_log_level          = "ALL"`) {
+		t.Errorf("expected template and project changes in appsync.tf, got\n%s", appsync)
+	}
+	if !strings.Contains(read("iam.tf"), "AWSAppSyncPushToCloudWatchLogs") {
+		t.Error("expected iam.tf to be updated")
+	}
+	if !strings.Contains(read("locals.tf"), conflictStartMarker) {
+		t.Errorf("expected conflict markers in locals.tf, got\n%s", read("locals.tf"))
+	}
+
+	manifest, err = project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	record = &manifest.Templates[0]
+	if record.Version != templateVersions[record.ID] {
+		t.Errorf("expected version %d, got %d", templateVersions[record.ID], record.Version)
+	}
+	if _, ok := record.Values["Destination"]; ok || record.Values["ProjectName"] != "users" {
+		t.Errorf("expected only the template values, got %v", record.Values)
+	}
+	if read(filepath.Join(BaseDirName, "iam.tf")) != read("iam.tf") {
+		t.Error("expected the base copy of iam.tf to be updated")
+	}
+}
+
+func TestUpgradeAdoptsProjectWithoutTemplates(t *testing.T) {
+	dest := t.TempDir()
+	api := &messages.CreateResourceMsg{
+		ID:                       helpers.ResourceIDs.CreateAppSyncAPI,
+		ProjectName:              "users",
+		AWSRegion:                "eu-central-1",
+		BackendBucket:            "bucket",
+		BackendKey:               "users/terraform.tfstate",
+		BackendLockTable:         "lock",
+		AuthorizerLambdaFunction: "authorizer",
+	}
+	if err := CreateResources(api.ID, dest, api); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dir := filepath.Join(dest, "users")
+	dataSource := &messages.CreateResourceMsg{
+		ID:            helpers.ResourceIDs.CreateAppSyncDataSource,
+		ProjectName:   "posts",
+		LambdaRuntime: "python3.11",
+	}
+	if err := CreateResources(dataSource.ID, dir, dataSource); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	read := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return string(content)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// projects generated before the manifest had an empty one and no base copies
+	write(project.ManifestFileName, "")
+	if err := os.RemoveAll(filepath.Join(dir, BaseDirName)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// an older template differing in a line, a project change and a line changed on both sides
+	write("iam.tf", strings.Replace(read("iam.tf"), "AWSAppSyncPushToCloudWatchLogs", "AdministratorAccess", 1))
+	write("appsync.tf", strings.Replace(read("appsync.tf"), `field_log_level          = "ERROR"`, `field_log_level          = "ALL"`, 1)+"\n# project change\n")
+	os.Remove(filepath.Join(dir, "outputs.tf"))
+
+	upgraded, err := Upgrade(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]FileStatus{
+		"appsync.tf": FileConflict,
+		"iam.tf":     FileConflict,
+		"outputs.tf": FileAdded,
+	}
+	statuses := map[string]FileStatus{}
+	for _, f := range upgraded {
+		statuses[f.Path] = f.Status
+	}
+	if len(statuses) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, statuses)
+	}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("expected %s to be %s, got %s", name, status, statuses[name])
+		}
+	}
+	// the lines added in the project are kept outside of the conflicts
+	if appsync := read("appsync.tf"); !strings.HasSuffix(appsync, "# project change\n") {
+		t.Errorf("expected the project change in appsync.tf, got\n%s", appsync)
+	}
+
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.Name != "users" || len(manifest.Templates) != 2 {
+		t.Fatalf("expected the API and the data source to be adopted, got %+v", manifest)
+	}
+	for i, want := range []*messages.CreateResourceMsg{api, dataSource} {
+		record := manifest.Templates[i]
+		if record.ID != want.ID || record.Version != templateVersions[want.ID] {
+			t.Errorf("unexpected record %s %d", record.ID, record.Version)
+		}
+		for name, value := range templateValues(want) {
+			if record.Values[name] != value {
+				t.Errorf("expected %s of %s to be %s, got %s", name, record.ID, value, record.Values[name])
+			}
+		}
+	}
+
+	upgraded, err = Upgrade(dir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(upgraded) != 0 {
+		t.Errorf("expected an up to date project, got %v", upgraded)
+	}
+}
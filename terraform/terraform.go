@@ -0,0 +1,123 @@
+package terraform
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// binaries are looked up on PATH in this order
+var binaries = []string{"terraform", "tofu"}
+
+// Step is a single terraform command run by the pipeline
+type Step struct {
+	Name string
+	Args []string
+}
+
+// ValidateSteps checks the generated project without touching the real backend
+var ValidateSteps = []Step{
+	{Name: "fmt", Args: []string{"fmt", "-recursive"}},
+	{Name: "init", Args: []string{"init", "-backend=false", "-input=false", "-no-color"}},
+	{Name: "validate", Args: []string{"validate", "-no-color"}},
+}
+
+// PlanSteps initializes the real backend and plans the changes
+var PlanSteps = []Step{
+	{Name: "init", Args: []string{"init", "-input=false", "-no-color"}},
+	{Name: "plan", Args: []string{"plan", "-input=false", "-no-color"}},
+}
+
+// EnvironmentPlanSteps returns the PlanSteps of an environment, initArgs select its backend key and
+// planArgs its variables
+func EnvironmentPlanSteps(initArgs, planArgs []string) []Step {
+	steps := make([]Step, 0, len(PlanSteps))
+	for _, step := range PlanSteps {
+		args := append([]string{}, step.Args...)
+		switch step.Name {
+		case "init":
+			args = append(args, initArgs...)
+		case "plan":
+			args = append(args, planArgs...)
+		}
+		steps = append(steps, Step{Name: step.Name, Args: args})
+	}
+	return steps
+}
+
+// Find returns the path of the terraform binary, or tofu when terraform is not installed
+func Find() (string, error) {
+	for _, name := range binaries {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("none of %s found in PATH", strings.Join(binaries, ", "))
+}
+
+// Runner runs terraform commands in a project directory
+type Runner struct {
+	Binary string
+	Dir    string
+}
+
+// NewRunner returns a Runner for the project in dir
+func NewRunner(binary, dir string) Runner {
+	return Runner{
+		Binary: binary,
+		Dir:    dir,
+	}
+}
+
+// Run runs the binary with args and sends every line of its output to out
+func (r Runner) Run(out chan<- string, args ...string) error {
+	cmd := exec.Command(r.Binary, args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=1", "TF_INPUT=0")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		out <- scanner.Text()
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("%s %s: %v", r.Binary, strings.Join(args, " "), err)
+	}
+
+	return scanner.Err()
+}
+
+// RunSteps runs the steps one after another and stops on the first failure
+func (r Runner) RunSteps(steps []Step, out chan<- string) error {
+	if len(steps) == 0 {
+		return errors.New("no steps to run")
+	}
+
+	for _, step := range steps {
+		out <- fmt.Sprintf("$ %s %s", r.name(), strings.Join(step.Args, " "))
+		if err := r.Run(out, step.Args...); err != nil {
+			return fmt.Errorf("%s failed: %v", step.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// name returns the binary name without its directory
+func (r Runner) name() string {
+	return r.Binary[strings.LastIndexAny(r.Binary, `/\`)+1:]
+}
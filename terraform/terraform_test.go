@@ -0,0 +1,172 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const fakeBinary = `#!/bin/sh
+echo "fake $@"
+if [ "$1" = "validate" ] && [ -n "$FAKE_TERRAFORM_FAIL" ]; then
+  echo "validation error" >&2
+  exit 1
+fi
+`
+
+// installFakeBinary puts a fake terraform-like binary as the only entry on PATH
+func installFakeBinary(t *testing.T, name string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(fakeBinary), 0755); err != nil {
+		t.Fatalf("unable to create fake binary: %v", err)
+	}
+	t.Setenv("PATH", dir)
+
+	return path
+}
+
+func TestFind(t *testing.T) {
+	tcs := []struct {
+		name        string
+		binary      string
+		expectError bool
+	}{
+		{
+			name:        "Terraform on PATH",
+			binary:      "terraform",
+			expectError: false,
+		},
+		{
+			name:        "Tofu on PATH",
+			binary:      "tofu",
+			expectError: false,
+		},
+		{
+			name:        "Nothing on PATH",
+			binary:      "terragrunt",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := installFakeBinary(t, tc.binary)
+
+			path, err := Find()
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if path != expected {
+				t.Errorf("expected %s, got %s", expected, path)
+			}
+		})
+	}
+}
+
+func TestRunSteps(t *testing.T) {
+	tcs := []struct {
+		name        string
+		fail        bool
+		expected    []string
+		expectError bool
+	}{
+		{
+			name: "All steps succeed",
+			fail: false,
+			expected: []string{
+				"$ terraform fmt -recursive",
+				"fake fmt -recursive",
+				"$ terraform init -backend=false -input=false -no-color",
+				"fake init -backend=false -input=false -no-color",
+				"$ terraform validate -no-color",
+				"fake validate -no-color",
+			},
+			expectError: false,
+		},
+		{
+			name: "Validate fails",
+			fail: true,
+			expected: []string{
+				"$ terraform fmt -recursive",
+				"fake fmt -recursive",
+				"$ terraform init -backend=false -input=false -no-color",
+				"fake init -backend=false -input=false -no-color",
+				"$ terraform validate -no-color",
+				"fake validate -no-color",
+				"validation error",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			binary := installFakeBinary(t, "terraform")
+			if tc.fail {
+				t.Setenv("FAKE_TERRAFORM_FAIL", "1")
+			}
+
+			out := make(chan string)
+			var lines []string
+			done := make(chan struct{})
+			go func() {
+				for line := range out {
+					lines = append(lines, line)
+				}
+				close(done)
+			}()
+
+			err := NewRunner(binary, t.TempDir()).RunSteps(ValidateSteps, out)
+			close(out)
+			<-done
+
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if strings.Join(lines, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected output\n%s\ngot\n%s", strings.Join(tc.expected, "\n"), strings.Join(lines, "\n"))
+			}
+		})
+	}
+}
+
+func TestEnvironmentPlanSteps(t *testing.T) {
+	steps := EnvironmentPlanSteps([]string{"-backend-config=environments/dev.s3.tfbackend"}, []string{"-var-file=environments/dev.tfvars"})
+
+	expected := []string{
+		"init -input=false -no-color -backend-config=environments/dev.s3.tfbackend",
+		"plan -input=false -no-color -var-file=environments/dev.tfvars",
+	}
+	if len(steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %d", len(expected), len(steps))
+	}
+	for i, step := range steps {
+		if args := strings.Join(step.Args, " "); args != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], args)
+		}
+	}
+	if args := strings.Join(PlanSteps[0].Args, " "); args != "init -input=false -no-color" {
+		t.Errorf("expected PlanSteps to be unchanged, got %s", args)
+	}
+}
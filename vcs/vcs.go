@@ -0,0 +1,155 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/xsevy/terrapi/project"
+)
+
+// default author of the commits when git has no user configured
+const (
+	defaultAuthorName  = "terrapi"
+	defaultAuthorEmail = "terrapi@localhost"
+)
+
+// ErrDirtyTree is returned when a project using git has uncommitted changes
+var ErrDirtyTree = errors.New("the git repository has uncommitted changes, commit or stash them first, or force the operation")
+
+// Init creates a git repository in the project dir and commits the generated files,
+// the .gitignore of the template keeps local terraform files out of the repository
+func Init(dir string, message string) error {
+	if _, err := git.PlainInit(dir, false); err != nil {
+		return fmt.Errorf("unable to initialize a git repository in %s: %w", dir, err)
+	}
+
+	return Commit(dir, message)
+}
+
+// Clean reports whether the git repository containing dir has no uncommitted changes
+func Clean(dir string) (bool, error) {
+	repo, err := open(dir)
+	if err != nil {
+		return false, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return false, err
+	}
+	return status.IsClean(), nil
+}
+
+// Commit stages every change in dir and commits it to the repository containing dir
+func Commit(dir string, message string) error {
+	repo, err := open(dir)
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	path, err := filepath.Rel(absolute(worktree.Filesystem.Root()), absolute(dir))
+	if err != nil {
+		return err
+	}
+	options := &git.AddOptions{All: true}
+	if path != "." {
+		options.Path = filepath.ToSlash(path)
+	}
+	if err := worktree.AddWithOptions(options); err != nil {
+		return fmt.Errorf("unable to stage the changes: %w", err)
+	}
+
+	_, err = worktree.Commit(message, &git.CommitOptions{Author: author(repo)})
+	if errors.Is(err, git.ErrEmptyCommit) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to commit the changes: %w", err)
+	}
+	return nil
+}
+
+// Run runs an operation changing the project in dir. Projects created with git must have
+// a clean tree unless force is set, and get one commit with the message for the operation.
+func Run(dir string, force bool, message string, operation func() error) error {
+	manifest, err := project.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	if !manifest.Git {
+		return operation()
+	}
+
+	if !force {
+		clean, err := Clean(dir)
+		if err != nil {
+			return err
+		}
+		if !clean {
+			return ErrDirtyTree
+		}
+	}
+
+	if err := operation(); err != nil {
+		return err
+	}
+
+	return Commit(dir, message)
+}
+
+// open opens the repository containing dir
+func open(dir string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the git repository of %s: %w", dir, err)
+	}
+	return repo, nil
+}
+
+// author returns the user configured in git, or the terrapi user
+func author(repo *git.Repository) *object.Signature {
+	signature := &object.Signature{
+		Name:  defaultAuthorName,
+		Email: defaultAuthorEmail,
+		When:  time.Now(),
+	}
+
+	cfg, err := repo.ConfigScoped(config.SystemScope)
+	if err != nil {
+		return signature
+	}
+	if cfg.User.Name != "" {
+		signature.Name = cfg.User.Name
+	}
+	if cfg.User.Email != "" {
+		signature.Email = cfg.User.Email
+	}
+	return signature
+}
+
+// absolute resolves dir the same way as the repository root, so they can be compared
+func absolute(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	return dir
+}
@@ -0,0 +1,105 @@
+package vcs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/xsevy/terrapi/project"
+)
+
+// commitMessages returns the messages of the commits of the repository in dir, newest first
+func commitMessages(t *testing.T, dir string) []string {
+	t.Helper()
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commits, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var messages []string
+	for {
+		commit, err := commits.Next()
+		if err != nil {
+			return messages
+		}
+		messages = append(messages, commit.Message)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestInitAndRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	writeFile(t, filepath.Join(dir, ".gitignore"), "*.tfstate\n")
+	writeFile(t, filepath.Join(dir, "main.tf"), "terraform {}\n")
+	writeFile(t, filepath.Join(dir, "terraform.tfstate"), "{}\n")
+	manifest := &project.Manifest{Name: "users", Git: true}
+	if err := manifest.Write(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := Init(dir, "Create AppSync API users"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clean, err := Clean(dir)
+	if err != nil || !clean {
+		t.Fatalf("expected a clean tree with ignored files, got %v %v", clean, err)
+	}
+
+	writeFile(t, filepath.Join(dir, "main.tf"), "terraform {}\n# edited\n")
+	operation := func() error {
+		writeFile(t, filepath.Join(dir, "datasources.tf"), "# data sources\n")
+		return nil
+	}
+
+	if err := Run(dir, false, "Add data source posts", operation); !errors.Is(err, ErrDirtyTree) {
+		t.Errorf("expected ErrDirtyTree, got %v", err)
+	}
+	if err := Run(dir, true, "Add data source posts", operation); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clean, err = Clean(dir)
+	if err != nil || !clean {
+		t.Errorf("expected the operation to be committed, got %v %v", clean, err)
+	}
+
+	messages := commitMessages(t, dir)
+	expected := []string{"Add data source posts", "Create AppSync API users"}
+	if len(messages) != len(expected) || messages[0] != expected[0] || messages[1] != expected[1] {
+		t.Errorf("expected commits %v, got %v", expected, messages)
+	}
+}
+
+func TestRunWithoutGit(t *testing.T) {
+	dir := t.TempDir()
+	if err := (&project.Manifest{Name: "users"}).Write(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ran := false
+	if err := Run(dir, false, "Add environment dev", func() error { ran = true; return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ran {
+		t.Error("expected the operation to run")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Errorf("expected no repository, got %v", err)
+	}
+}